@@ -4,44 +4,91 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"postgo/codegen"
 	"postgo/db"
 )
 
 func main() {
 	var outputDir = flag.String("output", "generated", "Répertoire de sortie pour les fichiers générés")
+	var schemaFile = flag.String("schema", "", "Lit les tables depuis un fichier de schéma YAML ou JSON au lieu de db/schema.go")
+	var introspect = flag.Bool("introspect", false, "Lit les tables depuis une base existante au lieu de db/schema.go")
+	var emitSchema = flag.String("emit-schema", "", "Avec -introspect, écrit les définitions de tables Go dans ce fichier")
+	var emitPackage = flag.String("emit-package", "schema", "Package Go du fichier écrit par -emit-schema")
+	var host = flag.String("host", "localhost", "Hôte PostgreSQL (avec -introspect)")
+	var port = flag.Int("port", 5432, "Port PostgreSQL (avec -introspect)")
+	var user = flag.String("user", "postgo", "Utilisateur PostgreSQL (avec -introspect)")
+	var password = flag.String("password", "postgo", "Mot de passe PostgreSQL (avec -introspect)")
+	var dbname = flag.String("dbname", "postgo", "Base de données PostgreSQL (avec -introspect)")
+	var pgSchema = flag.String("pgschema", "public", "Schéma PostgreSQL à lire (avec -introspect)")
 	flag.Parse()
 
 	fmt.Println("=== Générateur de code PostGO ===")
-	
-	// Créer le répertoire de sortie s'il n'existe pas
-	err := os.MkdirAll(*outputDir, 0755)
-	if err != nil {
-		panic(fmt.Errorf("impossible de créer le répertoire %s: %v", *outputDir, err))
-	}
 
-	// Obtenir toutes les tables du schéma
-	tables := db.GetAllTables()
-	if len(tables) == 0 {
+	// Utiliser le schéma par défaut, un fichier de schéma, ou les tables de la base de données en mode introspection
+	schema := db.DefaultSchema()
+	switch {
+	case *schemaFile != "" && *introspect:
+		fmt.Fprintln(os.Stderr, "-schema et -introspect ne peuvent pas être utilisés ensemble")
+		os.Exit(2)
+	case *schemaFile != "":
+		var err error
+		schema, err = db.LoadSchemaFile(*schemaFile)
+		if err != nil {
+			// Une erreur par ligne, au format fichier:ligne: message
+			fmt.Fprintf(os.Stderr, "Schéma invalide:\n%v\n", err)
+			os.Exit(1)
+		}
+	case *introspect:
+		var err error
+		schema, err = introspectSchema(*host, *port, *user, *password, *dbname, *pgSchema, *emitSchema, *emitPackage)
+		if err != nil {
+			panic(fmt.Errorf("erreur lors de l'introspection de la base %s: %v", *dbname, err))
+		}
+	}
+	if len(schema.ListTables()) == 0 {
 		fmt.Println("Aucune table trouvée dans le schéma")
 		return
 	}
 
-	fmt.Printf("Génération du code pour %d table(s)...\n", len(tables))
+	fmt.Printf("Génération du code pour %d table(s)...\n", len(schema.ListTables()))
 
-	// Générer le fichier principal avec les types et constantes
-	err = generateMainTypes(*outputDir)
+	if err := codegen.Generate(schema, *outputDir); err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("✓ Génération terminée dans le répertoire '%s'\n", *outputDir)
+}
+
+// introspectSchema lit les tables d'une base existante dans un nouveau schéma et, si
+// emitSchema est défini, écrit le code Go des définitions correspondantes dans le package emitPackage
+func introspectSchema(host string, port int, user, password, dbname, pgSchema, emitSchema, emitPackage string) (*db.Schema, error) {
+	conn, err := db.NewConnection(host, port, user, password, dbname)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	introspected, err := db.IntrospectTables(conn, pgSchema)
 	if err != nil {
-		panic(fmt.Errorf("erreur lors de la génération des types: %v", err))
+		return nil, err
 	}
 
-	// Générer un fichier pour chaque table
-	for tableName, table := range tables {
-		err = generateTableFile(*outputDir, tableName, table)
+	if emitSchema != "" {
+		source, err := db.GenerateSchemaSource(introspected, emitPackage)
 		if err != nil {
-			panic(fmt.Errorf("erreur lors de la génération de la table %s: %v", tableName, err))
+			return nil, err
 		}
-		fmt.Printf("✓ Table '%s' générée\n", tableName)
+		if err := os.WriteFile(emitSchema, source, 0644); err != nil {
+			return nil, err
+		}
+		fmt.Printf("✓ Définitions des tables écrites dans '%s'\n", emitSchema)
 	}
 
-	fmt.Printf("✓ Génération terminée dans le répertoire '%s'\n", *outputDir)
+	schema := db.NewSchema()
+	for _, table := range introspected {
+		if err := schema.Register(table); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"postgo/db"
+)
+
+func main() {
+	var dir = flag.String("dir", "migrations", "Répertoire contenant les fichiers de migration")
+	var steps = flag.Int("steps", 1, "Nombre de migrations à annuler avec la commande down")
+	var name = flag.String("name", "schema_changes", "Nom de la migration créée par la commande diff")
+	var host = flag.String("host", "localhost", "Hôte PostgreSQL")
+	var port = flag.Int("port", 5432, "Port PostgreSQL")
+	var user = flag.String("user", "postgo", "Utilisateur PostgreSQL")
+	var password = flag.String("password", "postgo", "Mot de passe PostgreSQL")
+	var dbname = flag.String("dbname", "postgo", "Base de données PostgreSQL")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: migrate [options] up|down|status|diff")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	migrations, err := db.LoadMigrations(*dir)
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := db.NewConnection(*host, *port, *user, *password, *dbname)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	migrator, err := db.NewMigrator(conn, migrations)
+	if err != nil {
+		panic(err)
+	}
+
+	switch flag.Arg(0) {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down(*steps)
+	case "status":
+		err = printStatus(migrator)
+	case "diff":
+		err = writeDiffMigration(conn, *dir, *name, migrations)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erreur: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printStatus affiche l'état de chaque migration
+func printStatus(migrator *db.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("Aucune migration trouvée")
+		return nil
+	}
+
+	for _, status := range statuses {
+		state := "en attente"
+		switch {
+		case status.Missing:
+			state = "appliquée (fichier introuvable)"
+		case status.Modified:
+			state = "appliquée (modifiée depuis !)"
+		case status.Applied:
+			state = "appliquée le " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%d_%s\t%s\n", status.Migration.Version, status.Migration.Name, state)
+	}
+
+	return nil
+}
+
+// writeDiffMigration compare le schéma déclaré avec la base de données et écrit
+// les différences dans une nouvelle migration numérotée après la dernière existante
+func writeDiffMigration(conn *db.Connection, dir, name string, migrations []db.Migration) error {
+	diff, err := db.DiffSchema(conn)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range diff.ExtraTables {
+		fmt.Printf("⚠️  La table '%s' existe en base mais pas dans le schéma (ignorée)\n", table)
+	}
+	for _, change := range diff.KeyChanges {
+		fmt.Printf("⚠️  La clé primaire de la table '%s' passe de %s à %s: conversion à écrire à la main\n",
+			change.Table, keyStrategyName(change.From), keyStrategyName(change.To))
+	}
+
+	if diff.IsEmpty() {
+		fmt.Println("La base de données est à jour, aucune migration créée")
+		return nil
+	}
+
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+	migration := diff.ToMigration(version, name)
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+	if err := os.WriteFile(base+".up.sql", []byte(migration.Up), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".down.sql", []byte(migration.Down), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Migration créée: %s.up.sql / %s.down.sql\n", base, base)
+	return nil
+}
+
+// keyStrategyName retourne le nom affiché d'une stratégie de clé primaire
+func keyStrategyName(strategy db.KeyStrategy) string {
+	if strategy == "" {
+		return "aucune clé id"
+	}
+	return string(strategy)
+}
@@ -0,0 +1,54 @@
+// Package codegen génère le code Go typé (package generated) à partir des tables
+// d'un db.Schema. Il est utilisé par cmd/generate et peut être appelé directement
+// par une application qui déclare son propre schéma, par exemple depuis un
+// programme lancé par go generate :
+//
+//	schema := db.NewSchema().MustRegister(createUserTable(), createPostTable())
+//	if err := codegen.Generate(schema, "generated"); err != nil {
+//		log.Fatal(err)
+//	}
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"postgo/db"
+	"postgo/logging"
+)
+
+// Generate écrit dans outputDir le code typé des tables du schéma : types.go,
+// enums.go si des types énumérés sont utilisés, puis un fichier par table
+func Generate(schema *db.Schema, outputDir string) error {
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+
+	// Créer le répertoire de sortie s'il n'existe pas
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("impossible de créer le répertoire %s: %v", outputDir, err)
+	}
+
+	// Générer le fichier principal avec les types et constantes
+	if err := generateMainTypes(outputDir); err != nil {
+		return fmt.Errorf("erreur lors de la génération des types: %v", err)
+	}
+
+	// Générer les types énumérés utilisés par les tables
+	tables := schema.GetAllTables()
+	if enums := collectEnums(tables); len(enums) > 0 {
+		if err := generateEnumsFile(outputDir, enums); err != nil {
+			return fmt.Errorf("erreur lors de la génération des types énumérés: %v", err)
+		}
+		logging.Info.Printf("%d type(s) énuméré(s) généré(s)", len(enums))
+	}
+
+	// Générer un fichier pour chaque table, dans l'ordre d'enregistrement
+	for _, tableName := range schema.ListTables() {
+		if err := generateTableFile(outputDir, tableName, tables[tableName], tables); err != nil {
+			return fmt.Errorf("erreur lors de la génération de la table %s: %v", tableName, err)
+		}
+		logging.Info.Printf("Table '%s' générée", tableName)
+	}
+
+	return nil
+}
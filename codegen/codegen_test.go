@@ -0,0 +1,97 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"postgo/db"
+)
+
+func TestGenerate(t *testing.T) {
+	users := func() *db.TableBuilder {
+		return db.NewTable("users").AddAttribute("email", db.Text).NotNull().Build()
+	}
+
+	tests := []struct {
+		name     string
+		tables   []*db.TableBuilder
+		contains map[string][]string
+		wantErr  string
+	}{
+		{
+			name: "tables liées",
+			tables: []*db.TableBuilder{
+				users(),
+				db.NewTable("posts").
+					AddAttribute("author_id", db.Integer).References("users", "id").Build().
+					AddAttribute("parent_id", db.Integer).References("posts", "id").Build(),
+			},
+		},
+		{
+			name: "relation en conflit avec une colonne",
+			tables: []*db.TableBuilder{
+				users(),
+				db.NewTable("posts").
+					AddAttribute("author", db.Text).Build().
+					AddAttribute("author_id", db.Integer).References("users", "id").Build(),
+				db.NewTable("projects").
+					AddAttribute("owner", db.Integer).References("users", "id").Build(),
+			},
+			contains: map[string][]string{
+				"posts.go":    {"func (b *PostsSelectBuilder) JoinAuthorUser()", "AuthorUser User"},
+				"projects.go": {"func (b *ProjectsSelectBuilder) JoinOwnerUser()", "OwnerUser User"},
+			},
+		},
+		{
+			name:    "colonne en conflit avec une méthode",
+			tables:  []*db.TableBuilder{db.NewTable("users").AddAttribute("select", db.Text).Build()},
+			wantErr: "entre en conflit avec la méthode Select générée",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := db.NewSchema()
+			for _, table := range tt.tables {
+				if err := schema.Register(table); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			outputDir := t.TempDir()
+			err := Generate(schema, outputDir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Chaque fichier généré est du Go syntaxiquement valide
+			files, err := filepath.Glob(filepath.Join(outputDir, "*.go"))
+			if err != nil || len(files) != 1+len(tt.tables) {
+				t.Fatalf("fichiers générés = %v (%v)", files, err)
+			}
+			for _, file := range files {
+				source, err := os.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := parser.ParseFile(token.NewFileSet(), file, source, 0); err != nil {
+					t.Errorf("%s: %v", filepath.Base(file), err)
+				}
+				for _, want := range tt.contains[filepath.Base(file)] {
+					if !strings.Contains(string(source), want) {
+						t.Errorf("%s ne contient pas %q", filepath.Base(file), want)
+					}
+				}
+			}
+		})
+	}
+}
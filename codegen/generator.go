@@ -0,0 +1,1525 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"postgo/db"
+	"postgo/logging"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// generateMainTypes génère le fichier avec les types principaux
+func generateMainTypes(outputDir string) error {
+	content := `// Code généré automatiquement - NE PAS MODIFIER
+package generated
+
+import (
+	"context"
+	"postgo/db/query"
+)
+
+// Types de base pour la validation
+type TableReference struct {
+	Name string
+}
+
+// Interface commune pour tous les builders d'insertion
+type InsertBuilder interface {
+	Execute(exec query.Executor) error
+	ExecuteContext(ctx context.Context, exec query.Executor) error
+	Build() (string, []interface{})
+}
+
+// Interface commune pour tous les builders d'upsert
+type UpsertBuilder interface {
+	Execute(exec query.Executor) error
+	ExecuteContext(ctx context.Context, exec query.Executor) error
+	Build() (string, []interface{})
+}
+
+// Interface commune pour tous les builders d'update
+type UpdateBuilder interface {
+	Execute(exec query.Executor) error
+	ExecuteContext(ctx context.Context, exec query.Executor) error
+	Build() (string, []interface{})
+	Where(condition query.Condition) UpdateBuilder
+}
+
+// Interface commune pour tous les builders de suppression
+type DeleteBuilder interface {
+	Execute(exec query.Executor) error
+	ExecuteContext(ctx context.Context, exec query.Executor) error
+	Build() (string, []interface{})
+	Where(condition query.Condition) DeleteBuilder
+}
+`
+
+	return writeFile(filepath.Join(outputDir, "types.go"), content)
+}
+
+// generateTableFile génère un fichier dédié pour chaque table. tables contient toutes
+// les tables du schéma, nécessaires aux jointures sur les clés étrangères.
+func generateTableFile(outputDir, tableName string, table *db.TableBuilder, tables map[string]*db.TableBuilder) error {
+	attributes := table.GetAttributes()
+	titleName := titleCase(tableName)
+
+	if err := checkColumnNames(attributes, tableName); err != nil {
+		return err
+	}
+
+	// Générer les colonnes typées exposées par la table
+	columnFields, columnValues := generateColumnComponents(attributes, tableName)
+	
+	// Générer les composants pour Insert
+	insertFields, insertMethods, insertRequiredChecks := generateInsertComponents(attributes, titleName, "Insert")
+	
+	// Générer les composants pour Update
+	updateFields, updateMethods := generateUpdateComponents(attributes, titleName)
+	
+	// Générer les composants pour Select
+	selectMethods := generateSelectComponents(attributes, titleName, tableName)
+	
+	// Générer le struct principal
+	mainStruct := generateMainStruct(attributes, titleName, tableName)
+
+	// Générer les méthodes RETURNING de Insert, Update et Delete
+	returningMethods := generateReturningComponents(attributes, titleName, tableName)
+
+	// Générer le builder d'upsert (INSERT ... ON CONFLICT)
+	upsertBuilder := generateUpsertComponents(table, titleName, tableName)
+
+	// Générer les jointures typées sur les clés étrangères
+	joinMethods := generateJoinComponents(table, tables, titleName, tableName)
+
+	// Générer la pagination par curseur de SelectResult
+	paginationMethods := generatePaginationComponents(table, titleName, tableName)
+	
+	content := fmt.Sprintf(`// Code généré automatiquement - NE PAS MODIFIER
+package generated
+
+%s
+
+%s// %sTable représente la table %s et expose ses colonnes typées
+// utilisables dans les conditions Where
+type %sTable struct {
+	name string
+%s
+}
+
+// Instance globale de la table %s
+var %s = &%sTable{
+	name: "%s",
+%s
+}
+
+// TableName retourne le nom de la table en base
+func (t *%sTable) TableName() string {
+	return t.name
+}
+
+// %sInsertBuilder permet d'insérer des données dans la table %s
+type %sInsertBuilder struct {
+	query *query.InsertQuery
+	err   error // première valeur invalide définie (type énuméré)
+%s
+}
+
+// %sUpdateBuilder permet de mettre à jour des données dans la table %s
+type %sUpdateBuilder struct {
+	query *query.UpdateQuery
+	err   error // première valeur invalide définie (type énuméré)
+%s
+}
+
+// %sDeleteBuilder permet de supprimer des données de la table %s
+type %sDeleteBuilder struct {
+	query *query.DeleteQuery
+}
+
+// %sSelectBuilder permet de sélectionner des données de la table %s
+type %sSelectBuilder struct {
+	query *query.SelectQuery
+}
+
+// %sSelectResult représente les résultats possibles d'une sélection
+type %sSelectResult struct {
+	selectedColumns []string
+	query           *query.SelectQuery
+	pagination      query.Pagination
+}
+
+// Insert crée un nouveau builder pour insérer dans la table %s
+func (t *%sTable) Insert() *%sInsertBuilder {
+	return &%sInsertBuilder{
+		query: query.NewInsertQuery("%s"),
+	}
+}
+
+// Update crée un nouveau builder pour mettre à jour la table %s
+func (t *%sTable) Update() *%sUpdateBuilder {
+	return &%sUpdateBuilder{
+		query: query.NewUpdateQuery("%s"),
+	}
+}
+
+// Delete crée un nouveau builder pour supprimer de la table %s
+func (t *%sTable) Delete() *%sDeleteBuilder {
+	return &%sDeleteBuilder{
+		query: query.NewDeleteQuery("%s"),
+	}
+}
+
+// Select crée un nouveau builder pour sélectionner dans la table %s
+func (t *%sTable) Select() *%sSelectBuilder {
+	return &%sSelectBuilder{
+		query: query.NewSelectQuery("%s"),
+	}
+}
+%s
+%s
+
+// Execute exécute la requête d'insertion sur une connexion ou dans une transaction
+func (b *%sInsertBuilder) Execute(exec query.Executor) error {
+	return b.ExecuteContext(context.Background(), exec)
+}
+
+// ExecuteContext exécute la requête d'insertion en respectant l'annulation et l'échéance du contexte
+func (b *%sInsertBuilder) ExecuteContext(ctx context.Context, exec query.Executor) error {
+	if err := b.checkRequired(); err != nil {
+		return err
+	}
+	sqlQuery, args := b.query.Build(), b.query.GetValues()
+	_, err := exec.ExecContext(ctx, sqlQuery, args...)
+	return err
+}
+
+// checkRequired vérifie que toutes les colonnes obligatoires ont été définies avec des valeurs valides
+func (b *%sInsertBuilder) checkRequired() error {
+	if b.err != nil {
+		return b.err
+	}
+%s
+	return nil
+}
+
+// Build retourne la requête SQL et les arguments pour l'insertion
+func (b *%sInsertBuilder) Build() (string, []interface{}) {
+	return b.query.Build(), b.query.GetValues()
+}
+
+// Where ajoute une condition WHERE à la requête d'update
+func (b *%sUpdateBuilder) Where(condition query.Condition) *%sUpdateBuilder {
+	b.query.Where(condition)
+	return b
+}
+
+// Execute exécute la requête d'update
+func (b *%sUpdateBuilder) Execute(exec query.Executor) error {
+	return b.ExecuteContext(context.Background(), exec)
+}
+
+// ExecuteContext exécute la requête d'update en respectant l'annulation et l'échéance du contexte
+func (b *%sUpdateBuilder) ExecuteContext(ctx context.Context, exec query.Executor) error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.query.GetColumns()) == 0 {
+		return fmt.Errorf("aucune colonne à mettre à jour")
+	}
+	sqlQuery, args := b.query.Build(), b.query.GetValues()
+	_, err := exec.ExecContext(ctx, sqlQuery, args...)
+	return err
+}
+
+// Build retourne la requête SQL et les arguments pour l'update
+func (b *%sUpdateBuilder) Build() (string, []interface{}) {
+	return b.query.Build(), b.query.GetValues()
+}
+
+// Where ajoute une condition WHERE à la requête de suppression
+func (b *%sDeleteBuilder) Where(condition query.Condition) *%sDeleteBuilder {
+	b.query.AddCondition(condition)
+	return b
+}
+
+// Execute exécute la requête de suppression
+func (b *%sDeleteBuilder) Execute(exec query.Executor) error {
+	return b.ExecuteContext(context.Background(), exec)
+}
+
+// ExecuteContext exécute la requête de suppression en respectant l'annulation et l'échéance du contexte
+func (b *%sDeleteBuilder) ExecuteContext(ctx context.Context, exec query.Executor) error {
+	sqlQuery, args := b.query.Build(), b.query.GetValues()
+	_, err := exec.ExecContext(ctx, sqlQuery, args...)
+	return err
+}
+
+// Build retourne la requête SQL et les arguments pour la suppression
+func (b *%sDeleteBuilder) Build() (string, []interface{}) {
+	return b.query.Build(), b.query.GetValues()
+}
+%s
+%s
+%s
+%s
+%s
+`,
+		generateImports(attributes), // imports
+		mainStruct,            // main struct
+		titleName, tableName,  // Table comment
+		titleName,             // type Table struct
+		columnFields,          // column fields
+		tableName,             // instance comment
+		titleName, titleName, tableName,  // var Table = &Table{name:}
+		columnValues,          // column values
+		titleName,             // TableName()
+		titleName, tableName,  // InsertBuilder comment
+		titleName,             // type InsertBuilder struct
+		insertFields,          // insert fields
+		titleName, tableName,  // UpdateBuilder comment
+		titleName,             // type UpdateBuilder struct
+		updateFields,          // update fields
+		titleName, tableName,  // DeleteBuilder comment
+		titleName,             // type DeleteBuilder struct
+		titleName, tableName,  // SelectBuilder comment
+		titleName,             // type SelectBuilder struct
+		titleName,             // SelectResult comment
+		titleName,             // type SelectResult struct
+		tableName,             // Insert() comment
+		titleName, titleName,  // Insert() function
+		titleName, tableName,  // return &InsertBuilder{NewInsertQuery()}
+		tableName,             // Update() comment
+		titleName, titleName,  // Update() function
+		titleName, tableName,  // return &UpdateBuilder{NewUpdateQuery()}
+		tableName,             // Delete() comment
+		titleName, titleName,  // Delete() function
+		titleName, tableName,  // return &DeleteBuilder{NewDeleteQuery()}
+		tableName,             // Select() comment
+		titleName, titleName,  // Select() function
+		titleName, tableName,  // return &SelectBuilder{NewSelectQuery()}
+		insertMethods,         // insert methods
+		updateMethods,         // update methods
+		titleName,             // Execute() for insert
+		titleName,             // ExecuteContext() for insert
+		titleName,             // checkRequired() for insert
+		insertRequiredChecks,  // required checks for insert
+		titleName,             // Build() for insert
+		titleName, titleName,  // Where() for update
+		titleName,             // Execute() for update
+		titleName,             // ExecuteContext() for update
+		titleName,             // Build() for update
+		titleName, titleName,  // Where() for delete
+		titleName,             // Execute() for delete
+		titleName,             // ExecuteContext() for delete
+		titleName,             // Build() for delete
+		selectMethods,         // select methods
+		returningMethods,      // returning methods
+		upsertBuilder,         // upsert builder
+		joinMethods,           // join methods
+		paginationMethods)     // pagination methods
+
+	return writeFile(filepath.Join(outputDir, tableName+".go"), content)
+}
+
+// generateInsertComponents génère les composants pour un builder d'insertion
+// (builder vaut "Insert" ou "Upsert")
+func generateInsertComponents(attributes []*db.Attribute, titleName, builder string) (fields, methods, requiredChecks string) {
+	var fieldDeclarations []string
+	var setMethods []string
+	var requiredChecksList []string
+	
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		
+		// Ignorer les colonnes auto-incrémentées (SERIAL, IDENTITY) et les colonnes
+		// calculées, dont PostgreSQL attribue lui-même la valeur
+		if attr.IsAutoIncrement() || attr.IsGenerated() {
+			continue
+		}
+		
+		titleAttrName := toCamelCase(attrName)
+		lowerAttrName := strings.ToLower(strings.ReplaceAll(attrName, "_", ""))
+		goType := attr.GetBaseGoType()
+		
+		// Déclaration du champ pour suivre si la valeur a été définie
+		fieldDeclarations = append(fieldDeclarations, fmt.Sprintf("	%sSet bool", lowerAttrName))
+		
+		// Méthode Set pour cet attribut
+		setMethod := fmt.Sprintf(`
+// Set%s définit la valeur pour la colonne %s
+func (b *%s%sBuilder) Set%s(value %s) *%s%sBuilder {
+	if b.%sSet {
+		panic("La colonne %s a déjà été définie")
+	}%s
+	b.query.AddColumn("%s").AddValue(%s)
+	b.%sSet = true
+	return b
+}`, titleAttrName, attrName, titleName, builder, titleAttrName, goType, titleName, builder, lowerAttrName, attrName, enumCheck(attr), attrName, bindValue(attr), lowerAttrName)
+		
+		setMethods = append(setMethods, setMethod)
+		setMethods = append(setMethods, generateNullAndClearMethods(attr, titleName, builder))
+		
+		// Vérification pour les champs obligatoires (NOT NULL sans valeur par défaut)
+		if attr.IsRequiredOnInsert() {
+			requiredCheck := fmt.Sprintf(`	if !b.%sSet {
+		return fmt.Errorf("la colonne obligatoire '%s' n'a pas été définie")
+	}`, lowerAttrName, attrName)
+			requiredChecksList = append(requiredChecksList, requiredCheck)
+		}
+	}
+	
+	return strings.Join(fieldDeclarations, "\n"), 
+		   strings.Join(setMethods, ""), 
+		   strings.Join(requiredChecksList, "\n")
+}
+
+// generateNullAndClearMethods génère, pour un builder ("Insert", "Update" ou "Upsert"),
+// la méthode ClearX qui annule la valeur définie pour la colonne et, si la colonne
+// est nullable, la méthode SetXNull qui lui affecte explicitement NULL
+func generateNullAndClearMethods(attr *db.Attribute, titleName, builder string) string {
+	attrName := attr.GetName()
+	titleAttrName := toCamelCase(attrName)
+	lowerAttrName := strings.ToLower(strings.ReplaceAll(attrName, "_", ""))
+
+	var methods string
+	if attr.IsNullable() {
+		methods += fmt.Sprintf(`
+// Set%sNull définit la valeur NULL pour la colonne %s
+func (b *%s%sBuilder) Set%sNull() *%s%sBuilder {
+	if b.%sSet {
+		panic("La colonne %s a déjà été définie")
+	}
+	b.query.AddColumn("%s").AddValue(nil)
+	b.%sSet = true
+	return b
+}`, titleAttrName, attrName, titleName, builder, titleAttrName, titleName, builder, lowerAttrName, attrName, attrName, lowerAttrName)
+	}
+
+	methods += fmt.Sprintf(`
+// Clear%s annule la valeur définie pour la colonne %s, qui peut alors être redéfinie
+func (b *%s%sBuilder) Clear%s() *%s%sBuilder {
+	b.query.RemoveColumn("%s")
+	b.%sSet = false
+	return b
+}`, titleAttrName, attrName, titleName, builder, titleAttrName, titleName, builder, attrName, lowerAttrName)
+
+	return methods
+}
+
+// generateUpdateComponents génère les composants pour le builder d'update
+func generateUpdateComponents(attributes []*db.Attribute, titleName string) (fields, methods string) {
+	var fieldDeclarations []string
+	var setMethods []string
+	
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		
+		// Ignorer la clé primaire car on ne devrait pas l'updater, ainsi que les colonnes calculées
+		if attr.IsPrimaryKey() || attr.IsGenerated() {
+			continue
+		}
+		
+		titleAttrName := toCamelCase(attrName)
+		lowerAttrName := strings.ToLower(strings.ReplaceAll(attrName, "_", ""))
+		goType := attr.GetBaseGoType()
+		
+		// Déclaration du champ pour suivre si la valeur a été définie
+		fieldDeclarations = append(fieldDeclarations, fmt.Sprintf("	%sSet bool", lowerAttrName))
+		
+		// Méthode Set pour cet attribut (pour Update)
+		setMethod := fmt.Sprintf(`
+// Set%s définit la valeur pour la colonne %s dans l'update
+func (b *%sUpdateBuilder) Set%s(value %s) *%sUpdateBuilder {
+	if b.%sSet {
+		panic("La colonne %s a déjà été définie")
+	}%s
+	b.query.AddColumn("%s").AddValue(%s)
+	b.%sSet = true
+	return b
+}`, titleAttrName, attrName, titleName, titleAttrName, goType, titleName, lowerAttrName, attrName, enumCheck(attr), attrName, bindValue(attr), lowerAttrName)
+		
+		setMethods = append(setMethods, setMethod)
+		setMethods = append(setMethods, generateNullAndClearMethods(attr, titleName, "Update"))
+	}
+	
+	return strings.Join(fieldDeclarations, "\n"), strings.Join(setMethods, "")
+}
+
+// generateSelectComponents génère les composants pour le builder de sélection
+func generateSelectComponents(attributes []*db.Attribute, titleName, tableName string) string {
+	var selectMethods []string
+	
+	// Utiliser le nom singulier pour le struct (ex: User au lieu de Users)
+	singularName := singularize(titleName)
+	
+	// Importer database/sql est nécessaire pour sql.ErrNoRows
+	
+	// Méthode SelectAll
+	selectMethods = append(selectMethods, fmt.Sprintf(`
+// SelectAll sélectionne toutes les colonnes de la table %s, nommées explicitement dans
+// l'ordre des champs du struct : l'ordre physique des colonnes (ADD COLUMN) n'a pas d'effet
+func (b *%sSelectBuilder) SelectAll() *%sSelectResult {
+	for _, column := range %sColumns {
+		b.query.AddColumn(column)
+	}
+	return &%sSelectResult{
+		selectedColumns: slices.Clone(%sColumns),
+		query:           b.query,
+	}
+}`, titleName, titleName, titleName, tableName, titleName, tableName))
+
+	// Méthodes pour chaque colonne sur le SelectBuilder
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		titleAttrName := toCamelCase(attrName)
+		
+		selectMethods = append(selectMethods, fmt.Sprintf(`
+// Select%s sélectionne la colonne %s
+func (b *%sSelectBuilder) Select%s() *%sSelectResult {
+	b.query.AddColumn("%s")
+	return &%sSelectResult{
+		selectedColumns: []string{"%s"},
+		query:           b.query,
+	}
+}`, titleAttrName, attrName, titleName, titleAttrName, titleName, attrName, titleName, attrName))
+	}
+
+	// Méthodes pour chaque colonne sur le SelectResult (pour permettre l'enchaînement)
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		titleAttrName := toCamelCase(attrName)
+		
+		selectMethods = append(selectMethods, fmt.Sprintf(`
+// Select%s ajoute la colonne %s à la sélection
+func (r *%sSelectResult) Select%s() *%sSelectResult {
+	r.query.AddColumn("%s")
+	r.selectedColumns = append(r.selectedColumns, "%s")
+	return r
+}`, titleAttrName, attrName, titleName, titleAttrName, titleName, attrName, attrName))
+	}
+
+	// Méthode SelectColumns générique
+	selectMethods = append(selectMethods, fmt.Sprintf(`
+// SelectColumns sélectionne plusieurs colonnes spécifiques
+func (b *%sSelectBuilder) SelectColumns(columns ...string) *%sSelectResult {
+	for _, column := range columns {
+		b.query.AddColumn(column)
+	}
+	return &%sSelectResult{
+		selectedColumns: columns,
+		query:           b.query,
+	}
+}`, titleName, titleName, titleName))
+
+	// Méthode Aggregate : les résultats ne correspondent pas aux champs du struct de la
+	// table, ils sont lus par query.Scan dans un struct de l'appelant ou par query.ScanValue
+	selectMethods = append(selectMethods, fmt.Sprintf(`
+// Aggregate sélectionne des agrégats et des colonnes de regroupement de la table %s
+// (ex: Aggregate(query.Count().As("total"))). La requête retournée accepte Where,
+// GroupBy et Having ; ses lignes sont lues avec query.Scan ou query.ScanValue.
+func (b *%sSelectBuilder) Aggregate(selections ...query.Selection) *query.SelectQuery {
+	return b.query.Select(selections...)
+}`, tableName, titleName))
+
+	// Méthodes WHERE typées pour SelectResult
+	var whereMethods []string
+	
+	whereMethods = append(whereMethods, fmt.Sprintf(`
+// Where ajoute une condition WHERE à la requête de sélection
+func (r *%sSelectResult) Where(condition query.Condition) *%sSelectResult {
+	r.query.Where(condition)
+	return r
+}`, titleName, titleName))
+
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		titleAttrName := toCamelCase(attrName)
+		goType := attr.GetBaseGoType()
+		
+		whereMethods = append(whereMethods, fmt.Sprintf(`
+// Where%s ajoute une condition d'égalité sur %s
+func (r *%sSelectResult) Where%s(%s %s) *%sSelectResult {
+	r.query.Where(%s.%s.Eq(%s))
+	return r
+}`, titleAttrName, attrName, titleName, titleAttrName, strings.ToLower(attrName), goType, titleName, titleName, titleAttrName, strings.ToLower(attrName)))
+	}
+
+	// Méthodes de tri, de limite et de dédoublonnage pour SelectResult
+	orderMethods := []string{fmt.Sprintf(`
+// OrderBy ajoute des critères de tri construits à partir des colonnes typées de %s
+// avec Asc ou Desc, suivis au besoin de NullsFirst ou NullsLast
+func (r *%sSelectResult) OrderBy(orderings ...query.Ordering) *%sSelectResult {
+	r.query.OrderBy(orderings...)
+	return r
+}
+
+// Limit définit le nombre maximal de lignes retournées
+func (r *%sSelectResult) Limit(limit int) *%sSelectResult {
+	r.query.Limit(limit)
+	return r
+}
+
+// Offset définit le nombre de lignes ignorées avant la première ligne retournée
+func (r *%sSelectResult) Offset(offset int) *%sSelectResult {
+	r.query.Offset(offset)
+	return r
+}
+
+// Distinct élimine les lignes en double du résultat
+func (r *%sSelectResult) Distinct() *%sSelectResult {
+	r.query.Distinct()
+	return r
+}
+
+// DistinctOn ne garde que la première ligne de chaque groupe de valeurs des colonnes données
+func (r *%sSelectResult) DistinctOn(columns ...query.Selection) *%sSelectResult {
+	r.query.DistinctOn(columns...)
+	return r
+}`, tableName, titleName, titleName, titleName, titleName, titleName, titleName, titleName, titleName, titleName, titleName)}
+
+	for _, attr := range attributes {
+		titleAttrName := toCamelCase(attr.GetName())
+		for _, direction := range []string{"Asc", "Desc"} {
+			orderMethods = append(orderMethods, fmt.Sprintf(`
+// OrderBy%s%s trie les résultats sur la colonne %s par ordre %s
+func (r *%sSelectResult) OrderBy%s%s() *%sSelectResult {
+	r.query.OrderBy(%s.%s.%s())
+	return r
+}`, titleAttrName, direction, attr.GetName(), directionLabels[direction], titleName, titleAttrName, direction, titleName, titleName, titleAttrName, direction))
+		}
+	}
+
+	// Méthodes Execute
+	executeMethods := fmt.Sprintf(`
+// Execute exécute la requête et retourne les résultats typés
+func (r *%sSelectResult) Execute(exec query.Executor) ([]%s, error) {
+	return r.ExecuteContext(context.Background(), exec)
+}
+
+// ExecuteContext exécute la requête en respectant l'annulation et l'échéance du contexte.
+// Toutes les lignes sont chargées en mémoire : utilisez Iter pour les gros volumes.
+func (r *%sSelectResult) ExecuteContext(ctx context.Context, exec query.Executor) ([]%s, error) {
+	var results []%s
+	for result, err := range r.Iter(ctx, exec) {
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Iter exécute la requête et parcourt les lignes une à une, sans les charger toutes en
+// mémoire. Une erreur d'exécution ou de lecture est produite comme dernier élément.
+// Les lignes sont fermées à la fin du parcours, y compris en cas de sortie anticipée (break).
+//
+//	for user, err := range generated.Users.Select().SelectAll().Iter(ctx, conn) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func (r *%sSelectResult) Iter(ctx context.Context, exec query.Executor) iter.Seq2[%s, error] {
+	return func(yield func(%s, error) bool) {
+		var result %s
+
+		rows, err := exec.QueryContext(ctx, r.query.Build(), r.query.GetValues()...)
+		if err != nil {
+			yield(result, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			result, err = r.scan(rows)
+			if err != nil {
+				yield(result, err)
+				return
+			}
+			if !yield(result, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(%s{}, err)
+		}
+	}
+}
+
+// scan lit la ligne courante dans le struct, selon les colonnes sélectionnées
+func (r *%sSelectResult) scan(rows *sql.Rows) (%s, error) {
+	var result %s
+
+	// Toutes les colonnes, sélectionnées dans l'ordre des champs par SelectAll
+	if slices.Equal(r.selectedColumns, %sColumns) {
+		return result, rows.Scan(%s)
+	}
+
+	// Scan seulement les colonnes sélectionnées
+	var scanTargets []interface{}
+	for _, col := range r.selectedColumns {
+		switch col {
+%s
+		}
+	}
+	return result, rows.Scan(scanTargets...)
+}
+
+// ExecuteOne exécute la requête et retourne un seul résultat
+func (r *%sSelectResult) ExecuteOne(exec query.Executor) (*%s, error) {
+	return r.ExecuteOneContext(context.Background(), exec)
+}
+
+// ExecuteOneContext exécute une copie de la requête limitée à une ligne (LIMIT 1) et
+// retourne le résultat en respectant le contexte, ou sql.ErrNoRows si aucune ligne ne
+// correspond. La requête de r, et sa limite éventuelle, ne sont pas modifiées.
+func (r *%sSelectResult) ExecuteOneContext(ctx context.Context, exec query.Executor) (*%s, error) {
+	one := &%sSelectResult{
+		selectedColumns: r.selectedColumns,
+		query:           r.query.Clone().Limit(1),
+	}
+	for result, err := range one.Iter(ctx, exec) {
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+// Build retourne la requête SQL pour la sélection
+func (r *%sSelectResult) Build() (string, []interface{}) {
+	return r.query.Build(), r.query.GetValues()
+}`,
+		titleName, singularName, // Execute
+		titleName, singularName, singularName, // ExecuteContext
+		titleName, singularName, singularName, singularName, singularName, // Iter
+		titleName, singularName, singularName, tableName, generateAllColumnsScan(attributes), generateColumnCases(attributes), // scan
+		titleName, singularName, // ExecuteOne
+		titleName, singularName, titleName, // ExecuteOneContext
+		titleName) // Build
+
+	return strings.Join(selectMethods, "") + strings.Join(whereMethods, "") + strings.Join(orderMethods, "") + executeMethods
+}
+
+// directionLabels associe le suffixe des méthodes OrderBy générées au sens du tri
+var directionLabels = map[string]string{
+	"Asc":  "croissant",
+	"Desc": "décroissant",
+}
+
+// typePackages associe le préfixe des types Go des colonnes au package à importer
+var typePackages = map[string]string{
+	"time.": "time",
+	"json.": "encoding/json",
+	"db.":   "postgo/db",
+}
+
+// generateImports génère le bloc d'import d'un fichier de table, avec les packages
+// des types de ses colonnes (time.Time, json.RawMessage, db.Decimal, pq pour les tableaux...)
+func generateImports(attributes []*db.Attribute) string {
+	imports := []string{"context", "database/sql", "fmt", "iter", "postgo/db/query", "slices"}
+	for _, attr := range attributes {
+		if isArrayColumn(attr) && !slices.Contains(imports, "github.com/lib/pq") {
+			imports = append(imports, "github.com/lib/pq")
+		}
+		goType := strings.TrimLeft(attr.GetGoType(), "*[]")
+		for prefix, pkg := range typePackages {
+			if strings.HasPrefix(goType, prefix) && !slices.Contains(imports, pkg) {
+				imports = append(imports, pkg)
+			}
+		}
+	}
+	sort.Strings(imports)
+
+	var block strings.Builder
+	block.WriteString("import (\n")
+	for _, pkg := range imports {
+		fmt.Fprintf(&block, "\t%q\n", pkg)
+	}
+	block.WriteString(")")
+	return block.String()
+}
+
+// reservedTableMembers liste les méthodes des structs XTable générés, avec lesquelles
+// le champ d'une colonne entrerait en conflit
+var reservedTableMembers = []string{"TableName", "Insert", "Update", "Delete", "Select"}
+
+// checkColumnNames vérifie qu'aucune colonne ne génère un champ en conflit avec les méthodes de la table
+func checkColumnNames(attributes []*db.Attribute, tableName string) error {
+	for _, attr := range attributes {
+		fieldName := toCamelCase(attr.GetName())
+		for _, member := range reservedTableMembers {
+			if fieldName == member {
+				return fmt.Errorf("la colonne '%s.%s' entre en conflit avec la méthode %s générée", tableName, attr.GetName(), member)
+			}
+		}
+	}
+	return nil
+}
+
+// generateColumnComponents génère les champs des colonnes typées de la table et leur initialisation
+func generateColumnComponents(attributes []*db.Attribute, tableName string) (fields, values string) {
+	var fieldDeclarations []string
+	var fieldValues []string
+
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		titleAttrName := toCamelCase(attrName)
+
+		// Les colonnes textuelles et booléennes ont des conditions supplémentaires (LIKE, IS TRUE),
+		// les tableaux leurs propres opérateurs (@>, &&, ANY)
+		var columnType, constructor string
+		switch goType := attr.GetBaseGoType(); {
+		case isArrayColumn(attr):
+			element := strings.TrimPrefix(goType, "[]")
+			columnType = fmt.Sprintf("query.ArrayColumn[%s]", element)
+			constructor = fmt.Sprintf("query.NewArrayColumn[%s]", element)
+		case goType == "string":
+			columnType, constructor = "query.StringColumn", "query.NewStringColumn"
+		case goType == "bool":
+			columnType, constructor = "query.BoolColumn", "query.NewBoolColumn"
+		default:
+			columnType = fmt.Sprintf("query.Column[%s]", goType)
+			constructor = fmt.Sprintf("query.NewColumn[%s]", goType)
+		}
+
+		fieldDeclarations = append(fieldDeclarations, fmt.Sprintf("	%s %s", titleAttrName, columnType))
+		fieldValues = append(fieldValues, fmt.Sprintf("	%s: %s(\"%s\", \"%s\"),", titleAttrName, constructor, tableName, attrName))
+	}
+
+	return strings.Join(fieldDeclarations, "\n"), strings.Join(fieldValues, "\n")
+}
+
+// generateReturningComponents génère les méthodes ExecuteReturning, qui lisent les lignes
+// complètes retournées par INSERT, UPDATE ou DELETE ... RETURNING dans le struct de la table
+func generateReturningComponents(attributes []*db.Attribute, titleName, tableName string) string {
+	singularName := singularize(titleName)
+
+	var columns []string
+	for _, attr := range attributes {
+		columns = append(columns, fmt.Sprintf("%q", attr.GetName()))
+	}
+
+	return fmt.Sprintf(`
+// %sColumns liste les colonnes de la table %s dans l'ordre des champs de %s
+var %sColumns = []string{%s}
+
+// scan%sRows lit des lignes complètes de la table %s puis ferme rows
+func scan%sRows(rows *sql.Rows) ([]%s, error) {
+	defer rows.Close()
+
+	var results []%s
+	for rows.Next() {
+		var result %s
+		if err := rows.Scan(%s); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// scanTargets retourne les destinations de Scan des champs de %s, dans l'ordre de %sColumns
+func (r *%s) scanTargets() []interface{} {
+	return []interface{}{%s}
+}
+
+// ExecuteReturning exécute l'insertion et retourne la ligne insérée, valeurs générées comprises (id...)
+func (b *%sInsertBuilder) ExecuteReturning(exec query.Executor) (*%s, error) {
+	return b.ExecuteReturningContext(context.Background(), exec)
+}
+
+// ExecuteReturningContext exécute l'insertion comme ExecuteReturning en respectant le contexte
+func (b *%sInsertBuilder) ExecuteReturningContext(ctx context.Context, exec query.Executor) (*%s, error) {
+	if err := b.checkRequired(); err != nil {
+		return nil, err
+	}
+	rows, err := b.query.Returning(%sColumns...).ExecuteReturningContext(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	results, err := scan%sRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &results[0], nil
+}
+
+// ExecuteReturning exécute l'update et retourne les lignes mises à jour
+func (b *%sUpdateBuilder) ExecuteReturning(exec query.Executor) ([]%s, error) {
+	return b.ExecuteReturningContext(context.Background(), exec)
+}
+
+// ExecuteReturningContext exécute l'update comme ExecuteReturning en respectant le contexte
+func (b *%sUpdateBuilder) ExecuteReturningContext(ctx context.Context, exec query.Executor) ([]%s, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.query.GetColumns()) == 0 {
+		return nil, fmt.Errorf("aucune colonne à mettre à jour")
+	}
+	rows, err := b.query.Returning(%sColumns...).ExecuteReturningContext(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	return scan%sRows(rows)
+}
+
+// ExecuteReturning exécute la suppression et retourne les lignes supprimées
+func (b *%sDeleteBuilder) ExecuteReturning(exec query.Executor) ([]%s, error) {
+	return b.ExecuteReturningContext(context.Background(), exec)
+}
+
+// ExecuteReturningContext exécute la suppression comme ExecuteReturning en respectant le contexte
+func (b *%sDeleteBuilder) ExecuteReturningContext(ctx context.Context, exec query.Executor) ([]%s, error) {
+	rows, err := b.query.Returning(%sColumns...).ExecuteReturningContext(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	return scan%sRows(rows)
+}`,
+		tableName, tableName, singularName, // columns comment
+		tableName, strings.Join(columns, ", "), // columns var
+		titleName, tableName, // scan comment
+		titleName, singularName, singularName, singularName, generateAllColumnsScan(attributes), // scan function
+		singularName, tableName, singularName, strings.ReplaceAll(generateAllColumnsScan(attributes), "&result.", "&r."), // scanTargets
+		titleName, singularName, // insert ExecuteReturning
+		titleName, singularName, tableName, titleName, // insert ExecuteReturningContext
+		titleName, singularName, // update ExecuteReturning
+		titleName, singularName, tableName, titleName, // update ExecuteReturningContext
+		titleName, singularName, // delete ExecuteReturning
+		titleName, singularName, tableName, titleName) // delete ExecuteReturningContext
+}
+
+// generateJoinComponents génère une jointure typée par clé étrangère vers une table du schéma.
+// La relation est nommée d'après la colonne sans son suffixe _id (author_id -> JoinAuthor) et
+// les lignes sont lues dans un struct composé (PostWithAuthor{Post, Author User}).
+func generateJoinComponents(table *db.TableBuilder, tables map[string]*db.TableBuilder, titleName, tableName string) string {
+	singularName := singularize(titleName)
+
+	// Noms déjà pris dans le struct composé : le struct embarqué et ses champs
+	taken := map[string]bool{singularName: true}
+	for _, attr := range table.GetAttributes() {
+		taken[toCamelCase(attr.GetName())] = true
+	}
+
+	// Nombre de clés étrangères vers chaque table : la table jointe reçoit un alias
+	// quand elle est jointe par plusieurs relations ou quand la table se référence elle-même
+	references := make(map[string]int)
+	for _, attr := range table.GetAttributes() {
+		if ref := attr.GetReference(); ref != nil {
+			references[ref.GetTable()]++
+		}
+	}
+
+	var joins []string
+	for _, attr := range table.GetAttributes() {
+		ref := attr.GetReference()
+		if ref == nil {
+			continue
+		}
+		if _, ok := tables[ref.GetTable()]; !ok {
+			continue
+		}
+
+		relationColumn := strings.TrimSuffix(attr.GetName(), "_id")
+		refSingular := singularize(titleCase(ref.GetTable()))
+
+		// Le champ de la relation ne doit masquer ni le struct embarqué ni l'un de ses champs :
+		// une colonne sans suffixe _id (owner -> accounts) donne la relation OwnerAccount
+		relationName := toCamelCase(relationColumn)
+		if taken[relationName] {
+			relationName += refSingular
+		}
+		if taken[relationName] {
+			logging.Warning.Printf("Jointure de la colonne '%s.%s' ignorée: la relation %s entre en conflit avec un champ de %s", tableName, attr.GetName(), relationName, singularName)
+			continue
+		}
+		taken[relationName] = true
+		composedName := singularName + "With" + relationName
+		selectName := titleName + "Join" + relationName + "Select"
+
+		// L'alias est préfixé par la table pour ne jamais être un mot réservé (user, order...)
+		joinedName := ref.GetTable()
+		joinMethod := fmt.Sprintf(`InnerJoin("%s", `, joinedName)
+		if ref.GetTable() == tableName || references[ref.GetTable()] > 1 {
+			joinedName = tableName + "_" + relationColumn
+			joinMethod = fmt.Sprintf(`JoinAs(query.JoinInner, "%s", "%s", `, ref.GetTable(), joinedName)
+		}
+
+		joins = append(joins, fmt.Sprintf(`
+// %s représente une ligne de la table %s jointe à sa relation %s (%s.%s)
+type %s struct {
+	%s
+	%s %s
+}
+
+// %s sélectionne les lignes de la table %s jointes à leur relation %s
+type %s struct {
+	query *query.SelectQuery
+}
+
+// Join%s joint à chaque ligne de la table %s la ligne de %s référencée par %s.
+// La jointure est interne : les lignes dont %s est NULL ne sont pas retournées.
+// Les conditions portent sur des colonnes qualifiées (%s.*, %s.*).
+func (b *%sSelectBuilder) Join%s() *%s {
+	for _, column := range %sColumns {
+		b.query.AddColumn("%s." + column)
+	}
+	for _, column := range %sColumns {
+		b.query.AddColumn("%s." + column)
+	}
+	b.query.%squery.EqColumns("%s.%s", "%s.%s"))
+	return &%s{query: b.query}
+}
+
+// Where ajoute une condition à la jointure
+func (s *%s) Where(condition query.Condition) *%s {
+	s.query.Where(condition)
+	return s
+}
+
+// OrderBy ajoute des critères de tri à la jointure
+func (s *%s) OrderBy(orderings ...query.Ordering) *%s {
+	s.query.OrderBy(orderings...)
+	return s
+}
+
+// Limit définit le nombre maximal de lignes composées retournées
+func (s *%s) Limit(limit int) *%s {
+	s.query.Limit(limit)
+	return s
+}
+
+// Offset définit le nombre de lignes ignorées avant la première ligne retournée
+func (s *%s) Offset(offset int) *%s {
+	s.query.Offset(offset)
+	return s
+}
+
+// Execute exécute la jointure et retourne les lignes composées
+func (s *%s) Execute(exec query.Executor) ([]%s, error) {
+	return s.ExecuteContext(context.Background(), exec)
+}
+
+// ExecuteContext exécute la jointure comme Execute en respectant le contexte
+func (s *%s) ExecuteContext(ctx context.Context, exec query.Executor) ([]%s, error) {
+	rows, err := exec.QueryContext(ctx, s.query.Build(), s.query.GetValues()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []%s
+	for rows.Next() {
+		var result %s
+		if err := rows.Scan(append(result.%s.scanTargets(), result.%s.scanTargets()...)...); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// Build retourne la requête SQL de la jointure
+func (s *%s) Build() (string, []interface{}) {
+	return s.query.Build(), s.query.GetValues()
+}`,
+			composedName, tableName, relationName, tableName, attr.GetName(), // composed comment
+			composedName, singularName, relationName, refSingular, // composed struct
+			selectName, tableName, relationName, // select comment
+			selectName, // select struct
+			relationName, tableName, ref.GetTable(), attr.GetName(), // Join comment
+			attr.GetName(), tableName, joinedName, // Join comment
+			titleName, relationName, selectName, // Join signature
+			tableName, tableName, // main columns
+			ref.GetTable(), joinedName, // joined columns
+			joinMethod, joinedName, ref.GetColumn(), tableName, attr.GetName(), // ON
+			selectName, // return
+			selectName, selectName, // Where
+			selectName, selectName, // OrderBy
+			selectName, selectName, // Limit
+			selectName, selectName, // Offset
+			selectName, composedName, // Execute
+			selectName, composedName, // ExecuteContext
+			composedName, composedName, singularName, relationName, // scan
+			selectName)) // Build
+	}
+
+	return strings.Join(joins, "\n")
+}
+
+// generatePaginationComponents génère la pagination par curseur de SelectResult : After,
+// Before et Page, qui s'appuient sur query.Pagination avec la clé primaire de la table
+// pour départager les lignes de même valeur de tri
+func generatePaginationComponents(table *db.TableBuilder, titleName, tableName string) string {
+	singularName := singularize(titleName)
+
+	var key []string
+	for _, attr := range table.GetPrimaryKey() {
+		key = append(key, fmt.Sprintf("%q", tableName+"."+attr.GetName()))
+	}
+
+	// Seules les colonnes NOT NULL de valeurs comparables peuvent servir de curseur
+	var sortable, cases []string
+	for _, attr := range table.GetAttributes() {
+		if !isSortable(attr) {
+			continue
+		}
+		sortable = append(sortable, fmt.Sprintf("%q", tableName+"."+attr.GetName()))
+		cases = append(cases, fmt.Sprintf(`	case "%s.%s":
+		return r.%s, true`, tableName, attr.GetName(), toCamelCase(attr.GetName())))
+	}
+
+	return fmt.Sprintf(`
+// %sPage est une page de lignes de la table %s obtenue par Page
+type %sPage = query.Page[%s]
+
+// %sPrimaryKey liste les colonnes qualifiées de la clé primaire de la table %s
+var %sPrimaryKey = []string{%s}
+
+// %sSortableColumns liste les colonnes qualifiées de la table %s utilisables dans le tri d'une pagination
+var %sSortableColumns = []string{%s}
+
+// cursorValue retourne la valeur d'une colonne qualifiée triable, encodée dans les curseurs de pagination
+func (r %s) cursorValue(column string) (interface{}, bool) {
+	switch column {
+%s
+	}
+	return nil, false
+}
+
+// After reprend la sélection après la ligne du curseur (NextCursor d'une page précédente)
+func (r *%sSelectResult) After(cursor string) *%sSelectResult {
+	r.pagination.After = cursor
+	return r
+}
+
+// Before reprend la sélection avant la ligne du curseur (PreviousCursor d'une page suivante)
+func (r *%sSelectResult) Before(cursor string) *%sSelectResult {
+	r.pagination.Before = cursor
+	return r
+}
+
+// Page exécute la sélection et retourne une page d'au plus size lignes, dans l'ordre du
+// tri complété par la clé primaire. Les colonnes du tri doivent être NOT NULL et sélectionnées,
+// et la sélection ne doit définir ni Limit ni Offset. r n'est pas modifié par Page.
+func (r *%sSelectResult) Page(exec query.Executor, size int) (*%sPage, error) {
+	return r.PageContext(context.Background(), exec, size)
+}
+
+// PageContext exécute la sélection comme Page en respectant le contexte
+func (r *%sSelectResult) PageContext(ctx context.Context, exec query.Executor, size int) (*%sPage, error) {
+	pagination := r.pagination
+	pagination.Key = %sPrimaryKey
+	pagination.Sortable = %sSortableColumns
+	pagination.Size = size
+	fetch := func(page *query.SelectQuery) ([]%s, error) {
+		result := &%sSelectResult{selectedColumns: r.selectedColumns, query: page}
+		return result.ExecuteContext(ctx, exec)
+	}
+	return query.Paginate(ctx, exec, r.query, pagination, fetch, %s.cursorValue)
+}`,
+		singularName, tableName, // Page comment
+		singularName, singularName, // Page alias
+		tableName, tableName, // PrimaryKey comment
+		tableName, strings.Join(key, ", "), // PrimaryKey var
+		tableName, tableName, // SortableColumns comment
+		tableName, strings.Join(sortable, ", "), // SortableColumns var
+		singularName, strings.Join(cases, "\n"), // cursorValue
+		titleName, titleName, // After
+		titleName, titleName, // Before
+		titleName, singularName, // Page
+		titleName, singularName, tableName, tableName, singularName, titleName, singularName) // PageContext
+}
+
+// generateUpsertComponents génère le builder d'upsert : une insertion qui, en conflit sur
+// une colonne unique ou une contrainte UNIQUE / PRIMARY KEY composite, met à jour la ligne
+// existante avec les valeurs définies (EXCLUDED) ou l'ignore avec DoNothing
+func generateUpsertComponents(table *db.TableBuilder, titleName, tableName string) string {
+	attributes := table.GetAttributes()
+	singularName := singularize(titleName)
+	fields, setMethods, requiredChecks := generateInsertComponents(attributes, titleName, "Upsert")
+
+	// Une colonne est une cible de conflit si elle est unique, ou si elle est à elle seule
+	// la clé primaire et que sa valeur est écrite par l'application (clé naturelle, UUID...)
+	key := table.GetPrimaryKey()
+	isConflictTarget := func(attr *db.Attribute) bool {
+		return attr.IsUnique() || (len(key) == 1 && key[0] == attr && !attr.IsAutoIncrement())
+	}
+
+	// Une méthode OnConflict par colonne cible de conflit
+	var conflictMethods []string
+	for _, attr := range attributes {
+		if !isConflictTarget(attr) {
+			continue
+		}
+		attrName := attr.GetName()
+		conflictMethods = append(conflictMethods, fmt.Sprintf(`
+// OnConflict%s cible les conflits sur la contrainte unique de la colonne %s
+func (b *%sUpsertBuilder) OnConflict%s() *%sUpsertBuilder {
+	b.conflictColumns = []string{"%s"}
+	return b
+}`, toCamelCase(attrName), attrName, titleName, toCamelCase(attrName), titleName, attrName))
+	}
+
+	// Une méthode OnConflict par contrainte UNIQUE ou PRIMARY KEY composite (ex: OnConflictCompanyIdSlug)
+	for _, constraint := range table.GetConstraints() {
+		columns := constraint.GetColumns()
+		if constraint.GetKind() == db.CheckConstraint || len(columns) == 0 {
+			continue
+		}
+		methodName := ""
+		for _, column := range columns {
+			methodName += toCamelCase(column)
+		}
+		if len(columns) == 1 && slices.ContainsFunc(attributes, func(attr *db.Attribute) bool {
+			return attr.GetName() == columns[0] && isConflictTarget(attr)
+		}) {
+			continue
+		}
+		conflictMethods = append(conflictMethods, fmt.Sprintf(`
+// OnConflict%s cible les conflits sur la contrainte %s (%s)
+func (b *%sUpsertBuilder) OnConflict%s() *%sUpsertBuilder {
+	b.conflictColumns = []string{"%s"}
+	return b
+}`, methodName, constraint.GetName(), strings.Join(columns, ", "), titleName, methodName, titleName, strings.Join(columns, `", "`)))
+	}
+
+	return fmt.Sprintf(`
+// %sUpsertBuilder permet d'insérer une ligne dans la table %s ou, en cas de conflit,
+// de mettre à jour la ligne existante (INSERT ... ON CONFLICT)
+type %sUpsertBuilder struct {
+	query           *query.InsertQuery
+	err             error // première valeur invalide définie (type énuméré)
+	conflictColumns []string
+	doNothing       bool
+%s
+}
+
+// Upsert crée un nouveau builder pour insérer ou mettre à jour dans la table %s
+func (t *%sTable) Upsert() *%sUpsertBuilder {
+	return &%sUpsertBuilder{
+		query: query.NewInsertQuery("%s"),
+	}
+}
+%s
+
+// DoNothing ignore l'insertion en cas de conflit au lieu de mettre à jour la ligne existante
+func (b *%sUpsertBuilder) DoNothing() *%sUpsertBuilder {
+	b.doNothing = true
+	return b
+}
+%s
+
+// checkRequired vérifie que toutes les colonnes obligatoires et la cible du conflit ont été définies
+func (b *%sUpsertBuilder) checkRequired() error {
+	if b.err != nil {
+		return b.err
+	}
+%s
+	if len(b.conflictColumns) == 0 && !b.doNothing {
+		return fmt.Errorf("aucune colonne de conflit définie: appelez une méthode OnConflict ou DoNothing")
+	}
+	return nil
+}
+
+// applyConflict ajoute la clause ON CONFLICT : les colonnes définies, hors cible du conflit,
+// remplacent celles de la ligne existante
+func (b *%sUpsertBuilder) applyConflict() {
+	b.query.OnConflict(b.conflictColumns...)
+	if b.doNothing {
+		b.query.DoNothing()
+		return
+	}
+	for _, column := range b.query.GetColumns() {
+		if !slices.Contains(b.conflictColumns, column) {
+			b.query.DoUpdateExcluded(column)
+		}
+	}
+}
+
+// Execute exécute l'upsert sur une connexion ou dans une transaction
+func (b *%sUpsertBuilder) Execute(exec query.Executor) error {
+	return b.ExecuteContext(context.Background(), exec)
+}
+
+// ExecuteContext exécute l'upsert en respectant l'annulation et l'échéance du contexte
+func (b *%sUpsertBuilder) ExecuteContext(ctx context.Context, exec query.Executor) error {
+	if err := b.checkRequired(); err != nil {
+		return err
+	}
+	b.applyConflict()
+	sqlQuery, args := b.query.Build(), b.query.GetValues()
+	_, err := exec.ExecContext(ctx, sqlQuery, args...)
+	return err
+}
+
+// ExecuteReturning exécute l'upsert et retourne la ligne insérée ou mise à jour.
+// Avec DoNothing, un conflit ne retourne aucune ligne et l'erreur est sql.ErrNoRows.
+func (b *%sUpsertBuilder) ExecuteReturning(exec query.Executor) (*%s, error) {
+	return b.ExecuteReturningContext(context.Background(), exec)
+}
+
+// ExecuteReturningContext exécute l'upsert comme ExecuteReturning en respectant le contexte
+func (b *%sUpsertBuilder) ExecuteReturningContext(ctx context.Context, exec query.Executor) (*%s, error) {
+	if err := b.checkRequired(); err != nil {
+		return nil, err
+	}
+	b.applyConflict()
+	rows, err := b.query.Returning(%sColumns...).ExecuteReturningContext(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	results, err := scan%sRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &results[0], nil
+}
+
+// Build retourne la requête SQL et les arguments pour l'upsert
+func (b *%sUpsertBuilder) Build() (string, []interface{}) {
+	b.applyConflict()
+	return b.query.Build(), b.query.GetValues()
+}`,
+		titleName, tableName, // UpsertBuilder comment
+		titleName, fields, // type UpsertBuilder struct
+		tableName, titleName, titleName, titleName, tableName, // Upsert()
+		strings.Join(conflictMethods, ""), // OnConflict methods
+		titleName, titleName, // DoNothing()
+		setMethods,                // set methods
+		titleName, requiredChecks, // checkRequired()
+		titleName,             // applyConflict()
+		titleName, titleName, // Execute() / ExecuteContext()
+		titleName, singularName, // ExecuteReturning()
+		titleName, singularName, tableName, titleName, // ExecuteReturningContext()
+		titleName) // Build()
+}
+
+// enumCheck génère, pour une colonne de type énuméré, la vérification de la valeur
+// passée au setter : la première valeur invalide est conservée et retournée par Execute
+func enumCheck(attr *db.Attribute) string {
+	if attr.GetEnum() == nil {
+		return ""
+	}
+	return fmt.Sprintf(`
+	if !value.IsValid() && b.err == nil {
+		b.err = fmt.Errorf("valeur %%q invalide pour la colonne %s de type %s", value)
+	}`, attr.GetName(), attr.GetEnum().GetName())
+}
+
+// collectEnums retourne les types énumérés utilisés par les tables, triés par nom
+func collectEnums(tables map[string]*db.TableBuilder) []*db.Enum {
+	var enums []*db.Enum
+	seen := make(map[string]bool)
+	for _, table := range tables {
+		for _, attr := range table.GetAttributes() {
+			if enum := attr.GetEnum(); enum != nil && !seen[enum.GetName()] {
+				seen[enum.GetName()] = true
+				enums = append(enums, enum)
+			}
+		}
+	}
+	sort.Slice(enums, func(i, j int) bool {
+		return enums[i].GetName() < enums[j].GetName()
+	})
+	return enums
+}
+
+// generateEnumsFile génère le fichier des types énumérés : un type string par enum,
+// une constante par valeur et les fonctions de validation
+func generateEnumsFile(outputDir string, enums []*db.Enum) error {
+	var content strings.Builder
+	content.WriteString(`// Code généré automatiquement - NE PAS MODIFIER
+package generated
+
+import (
+	"fmt"
+	"slices"
+)
+`)
+
+	for _, enum := range enums {
+		goType := enum.GetGoType()
+
+		var constants, values []string
+		for _, value := range enum.GetValues() {
+			constantName := goType + db.GoIdentifier(value)
+			constants = append(constants, fmt.Sprintf("	%s %s = %q", constantName, goType, value))
+			values = append(values, constantName)
+		}
+
+		fmt.Fprintf(&content, `
+// %s représente le type énuméré %s
+type %s string
+
+// Valeurs du type énuméré %s
+const (
+%s
+)
+
+// %sValues liste les valeurs du type énuméré %s dans leur ordre de déclaration
+var %sValues = []%s{%s}
+
+// IsValid vérifie que la valeur appartient au type énuméré %s
+func (v %s) IsValid() bool {
+	return slices.Contains(%sValues, v)
+}
+
+// Parse%s convertit une chaîne en %s, ou retourne une erreur si elle n'appartient pas au type
+func Parse%s(value string) (%s, error) {
+	if !%s(value).IsValid() {
+		return "", fmt.Errorf("valeur %%q invalide pour le type %s", value)
+	}
+	return %s(value), nil
+}
+`,
+			goType, enum.GetName(), // type comment
+			goType,          // type
+			enum.GetName(),  // constants comment
+			strings.Join(constants, "\n"), // constants
+			goType, enum.GetName(), // Values comment
+			goType, goType, strings.Join(values, ", "), // Values
+			enum.GetName(), // IsValid comment
+			goType, goType, // IsValid
+			goType, goType, // Parse comment
+			goType, goType, goType, enum.GetName(), goType) // Parse
+	}
+
+	return writeFile(filepath.Join(outputDir, "enums.go"), content.String())
+}
+
+// isSortable vérifie si la colonne peut servir de curseur de pagination : NOT NULL et
+// de valeurs comparables une fois encodées (ni tableau, ni JSON, ni binaire)
+func isSortable(attr *db.Attribute) bool {
+	switch attr.GetBaseGoType() {
+	case "json.RawMessage", "[]byte", "interface{}":
+		return false
+	}
+	return !attr.IsNullable() && !isArrayColumn(attr)
+}
+
+// isArrayColumn vérifie si la colonne est un tableau lu et écrit sous forme de slice
+func isArrayColumn(attr *db.Attribute) bool {
+	return attr.IsArray() && strings.HasPrefix(attr.GetBaseGoType(), "[]")
+}
+
+// bindValue retourne l'expression passée en paramètre lié pour la valeur d'un setter,
+// les tableaux étant convertis par pq.Array
+func bindValue(attr *db.Attribute) string {
+	if isArrayColumn(attr) {
+		return "pq.Array(value)"
+	}
+	return "value"
+}
+
+// scanTarget retourne la destination de Scan pour un champ, les tableaux étant lus par pq.Array
+func scanTarget(attr *db.Attribute, field string) string {
+	if isArrayColumn(attr) {
+		return "pq.Array(" + field + ")"
+	}
+	return field
+}
+
+// generateAllColumnsScan génère le code pour scanner toutes les colonnes
+func generateAllColumnsScan(attributes []*db.Attribute) string {
+	var scans []string
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		scans = append(scans, scanTarget(attr, "&result."+toCamelCase(attrName)))
+	}
+	return strings.Join(scans, ", ")
+}
+
+// generateColumnCases génère les cases pour le switch des colonnes
+func generateColumnCases(attributes []*db.Attribute) string {
+	var cases []string
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		titleAttrName := toCamelCase(attrName)
+		cases = append(cases, fmt.Sprintf(`		case "%s":
+			scanTargets = append(scanTargets, %s)`, attrName, scanTarget(attr, "&result."+titleAttrName)))
+	}
+	return strings.Join(cases, "\n")
+}
+
+// singularize retourne le nom singulier utilisé pour le struct d'une ligne (ex: Users -> User)
+func singularize(titleName string) string {
+	if strings.HasSuffix(titleName, "s") {
+		return titleName[:len(titleName)-1]
+	}
+	return titleName
+}
+
+// toCamelCase convertit une chaîne snake_case en CamelCase
+func toCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i, part := range parts {
+		parts[i] = capitalize(part)
+	}
+	return strings.Join(parts, "")
+}
+
+// capitalize met en majuscule la première lettre d'une chaîne
+func capitalize(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	first, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(first)) + strings.ToLower(s[size:])
+}
+
+// titleCase remplace strings.Title deprecated
+func titleCase(s string) string {
+	// Les noms de tables en snake_case donnent un identifiant Go en PascalCase (post_categories -> PostCategories)
+	return toCamelCase(s)
+}
+
+// writeFile écrit le contenu dans un fichier
+func writeFile(filename, content string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(content)
+	return err
+}
+
+// generateMainStruct génère le struct principal représentant une ligne de la table
+func generateMainStruct(attributes []*db.Attribute, titleName, tableName string) string {
+	var fields []string
+	
+	// Utiliser le nom singulier pour le struct (ex: User au lieu de Users)
+	singularName := singularize(titleName)
+	
+	for _, attr := range attributes {
+		attrName := attr.GetName()
+		titleAttrName := toCamelCase(attrName)
+		goType := attr.GetGoType()
+		
+		fields = append(fields, fmt.Sprintf("	%s %s `db:\"%s\"`", titleAttrName, goType, attrName))
+	}
+	
+	return fmt.Sprintf(`// %s représente une ligne de la table %s
+type %s struct {
+%s
+}
+
+`, singularName, tableName, singularName, strings.Join(fields, "\n"))
+}
@@ -0,0 +1,291 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// liveTable représente une table telle qu'elle existe dans la base de données,
+// lue depuis le catalogue PostgreSQL
+type liveTable struct {
+	name        string
+	columns     []*liveColumn
+	constraints []*liveConstraint
+	indexes     []*liveIndex
+}
+
+// liveColumn représente une colonne lue depuis le catalogue
+type liveColumn struct {
+	name string
+	// dataType est le type tel que retourné par format_type (ex: "character varying(255)")
+	dataType    string
+	notNull     bool
+	defaultExpr string
+	// generated indique une colonne GENERATED ALWAYS AS (...) STORED, dont
+	// defaultExpr contient alors l'expression de calcul
+	generated bool
+	// identity est le mode d'une colonne GENERATED ... AS IDENTITY, vide pour les autres colonnes
+	identity IdentityGeneration
+	// enumName est le nom du type énuméré de la colonne, vide pour les autres types
+	enumName string
+	// enumValues contient les valeurs du type énuméré, dans l'ordre de pg_enum.enumsortorder
+	enumValues []string
+}
+
+// liveConstraint représente une contrainte lue depuis pg_constraint
+type liveConstraint struct {
+	name string
+	// kind est le contype PostgreSQL: 'p' (primary key), 'u' (unique), 'f' (foreign key),
+	// 'c' (check) ou 'x' (exclusion)
+	kind              string
+	columns           []string
+	referencedTable   string
+	referencedColumns []string
+	onDelete          ReferentialAction
+	onUpdate          ReferentialAction
+	definition        string
+}
+
+// liveIndex représente un index lu depuis pg_index, hors index créés par une contrainte
+type liveIndex struct {
+	name   string
+	unique bool
+	// method est la méthode d'accès (pg_am.amname, ex: "btree")
+	method string
+	// columns contient les colonnes de la clé de l'index, une chaîne vide désignant une expression
+	columns    []string
+	expression string
+	// predicate est le prédicat d'un index partiel, vide sinon
+	predicate string
+	// valid est faux pour un index dont la construction (CONCURRENTLY) a échoué
+	valid bool
+	// included indique des colonnes INCLUDE, ordered un tri DESC ou NULLS FIRST/LAST sur la clé
+	included bool
+	ordered  bool
+	// definition est l'instruction de création de l'index retournée par pg_get_indexdef
+	definition string
+}
+
+// column retourne la colonne portant le nom donné, ou nil
+func (t *liveTable) column(name string) *liveColumn {
+	for _, column := range t.columns {
+		if column.name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// columnConstraint retourne la contrainte du type donné portant uniquement sur la colonne, ou nil
+func (t *liveTable) columnConstraint(kind, column string) *liveConstraint {
+	for _, constraint := range t.constraints {
+		if constraint.kind == kind && len(constraint.columns) == 1 && constraint.columns[0] == column {
+			return constraint
+		}
+	}
+	return nil
+}
+
+// constraintNamed retourne la contrainte en base portant le nom donné, ou nil
+func (t *liveTable) constraintNamed(name string) *liveConstraint {
+	for _, constraint := range t.constraints {
+		if constraint.name == name {
+			return constraint
+		}
+	}
+	return nil
+}
+
+// index retourne l'index en base portant le nom donné, ou nil
+func (t *liveTable) index(name string) *liveIndex {
+	for _, index := range t.indexes {
+		if index.name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// primaryKey retourne la contrainte PRIMARY KEY de la table en base, ou nil
+func (t *liveTable) primaryKey() *liveConstraint {
+	for _, constraint := range t.constraints {
+		if constraint.kind == "p" {
+			return constraint
+		}
+	}
+	return nil
+}
+
+// readEnums lit les types énumérés d'un schéma PostgreSQL, un nom de schéma vide désignant
+// le schéma courant. Les valeurs de chaque type sont dans l'ordre de pg_enum.enumsortorder.
+func readEnums(ctx context.Context, conn *Connection, schemaName string) (map[string][]string, error) {
+	rows, err := conn.db.QueryContext(ctx, `
+		SELECT t.typname,
+		       ARRAY(SELECT e.enumlabel FROM pg_enum e WHERE e.enumtypid = t.oid ORDER BY e.enumsortorder)::text[]
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE t.typtype = 'e' AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire les types énumérés du catalogue: %w", err)
+	}
+	defer rows.Close()
+
+	enums := make(map[string][]string)
+	for rows.Next() {
+		var name string
+		var values []string
+		if err := rows.Scan(&name, pq.Array(&values)); err != nil {
+			return nil, err
+		}
+		enums[name] = values
+	}
+	return enums, rows.Err()
+}
+
+// readCatalog lit les tables, colonnes, contraintes et index d'un schéma PostgreSQL.
+// Un nom de schéma vide désigne le schéma courant (généralement "public").
+// Les tables sont retournées par ordre alphabétique.
+func readCatalog(ctx context.Context, conn *Connection, schemaName string) ([]*liveTable, error) {
+	rows, err := conn.db.QueryContext(ctx, `
+		SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+		       COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), a.attgenerated = 's',
+		       CASE a.attidentity WHEN 'a' THEN 'ALWAYS' WHEN 'd' THEN 'BY DEFAULT' ELSE '' END,
+		       CASE WHEN t.typtype = 'e' THEN t.typname ELSE '' END,
+		       ARRAY(SELECT e.enumlabel FROM pg_enum e WHERE e.enumtypid = t.oid ORDER BY e.enumsortorder)::text[]
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+		JOIN pg_type t ON t.oid = a.atttypid
+		LEFT JOIN pg_attrdef d ON d.adrelid = c.oid AND d.adnum = a.attnum
+		WHERE c.relkind = 'r' AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+		ORDER BY c.relname, a.attnum`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire les colonnes du catalogue: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []*liveTable
+	byName := make(map[string]*liveTable)
+	for rows.Next() {
+		var tableName string
+		column := &liveColumn{}
+		if err := rows.Scan(&tableName, &column.name, &column.dataType, &column.notNull, &column.defaultExpr, &column.generated, &column.identity,
+			&column.enumName, pq.Array(&column.enumValues)); err != nil {
+			return nil, err
+		}
+
+		table, exists := byName[tableName]
+		if !exists {
+			table = &liveTable{name: tableName}
+			byName[tableName] = table
+			tables = append(tables, table)
+		}
+		table.columns = append(table.columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	constraintRows, err := conn.db.QueryContext(ctx, `
+		SELECT c.relname, con.conname, con.contype,
+		       ARRAY(SELECT a.attname FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		             JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		             ORDER BY k.ord)::text[],
+		       COALESCE(fc.relname, ''),
+		       ARRAY(SELECT a.attname FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+		             JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+		             ORDER BY k.ord)::text[],
+		       con.confdeltype, con.confupdtype, pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_class fc ON fc.oid = con.confrelid
+		WHERE con.contype IN ('p', 'u', 'f', 'c', 'x') AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+		ORDER BY c.relname, con.conname`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire les contraintes du catalogue: %w", err)
+	}
+	defer constraintRows.Close()
+
+	for constraintRows.Next() {
+		var tableName, onDelete, onUpdate string
+		constraint := &liveConstraint{}
+		err := constraintRows.Scan(&tableName, &constraint.name, &constraint.kind,
+			pq.Array(&constraint.columns), &constraint.referencedTable, pq.Array(&constraint.referencedColumns),
+			&onDelete, &onUpdate, &constraint.definition)
+		if err != nil {
+			return nil, err
+		}
+
+		constraint.onDelete = referentialActionFromCode(onDelete)
+		constraint.onUpdate = referentialActionFromCode(onUpdate)
+
+		if table, exists := byName[tableName]; exists {
+			table.constraints = append(table.constraints, constraint)
+		}
+	}
+
+	if err := constraintRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Les index qui portent une contrainte (PRIMARY KEY, UNIQUE, EXCLUDE) sont déjà
+	// représentés par celle-ci
+	indexRows, err := conn.db.QueryContext(ctx, `
+		SELECT c.relname, i.relname, ix.indisunique, am.amname,
+		       ARRAY(SELECT COALESCE(a.attname, '') FROM unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		             LEFT JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+		             WHERE k.ord <= ix.indnkeyatts
+		             ORDER BY k.ord)::text[],
+		       COALESCE(pg_get_expr(ix.indexprs, ix.indrelid), ''), COALESCE(pg_get_expr(ix.indpred, ix.indrelid), ''),
+		       ix.indisvalid, ix.indnatts > ix.indnkeyatts,
+		       EXISTS (SELECT 1 FROM unnest(ix.indoption::int2[]) AS o(flags) WHERE o.flags <> 0),
+		       pg_get_indexdef(ix.indexrelid)
+		FROM pg_index ix
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_class c ON c.oid = ix.indrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_am am ON am.oid = i.relam
+		WHERE c.relkind = 'r' AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+		  AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.contype IN ('p', 'u', 'x') AND con.conindid = ix.indexrelid)
+		ORDER BY c.relname, i.relname`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire les index du catalogue: %w", err)
+	}
+	defer indexRows.Close()
+
+	for indexRows.Next() {
+		var tableName string
+		index := &liveIndex{}
+		err := indexRows.Scan(&tableName, &index.name, &index.unique, &index.method, pq.Array(&index.columns),
+			&index.expression, &index.predicate, &index.valid, &index.included, &index.ordered, &index.definition)
+		if err != nil {
+			return nil, err
+		}
+
+		if table, exists := byName[tableName]; exists {
+			table.indexes = append(table.indexes, index)
+		}
+	}
+
+	return tables, indexRows.Err()
+}
+
+// referentialActionFromCode convertit un code confdeltype/confupdtype en ReferentialAction.
+// NO ACTION, le comportement par défaut de PostgreSQL, est représenté par une action vide.
+func referentialActionFromCode(code string) ReferentialAction {
+	switch code {
+	case "c":
+		return Cascade
+	case "n":
+		return SetNull
+	case "r":
+		return Restrict
+	case "d":
+		return ReferentialAction("SET DEFAULT")
+	default:
+		return ""
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -15,6 +16,12 @@ type Connection struct {
 // Cette fonction construit la chaîne de connexion et teste la connectivité
 // avant de retourner l'instance de Connection.
 func NewConnection(host string, port int, user, password, dbname string) (*Connection, error) {
+	return NewConnectionContext(context.Background(), host, port, user, password, dbname)
+}
+
+// NewConnectionContext initialise une nouvelle connexion comme NewConnection,
+// le test de connectivité respectant l'annulation et l'échéance du contexte.
+func NewConnectionContext(ctx context.Context, host string, port int, user, password, dbname string) (*Connection, error) {
 	// Construction de la chaîne de connexion PostgreSQL
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
@@ -25,7 +32,8 @@ func NewConnection(host string, port int, user, password, dbname string) (*Conne
 	}
 
 	// Test de la connexion pour s'assurer qu'elle fonctionne
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -41,3 +49,33 @@ func (c *Connection) Close() error {
 func (c *Connection) GetDB() *sql.DB {
 	return c.db
 }
+
+// Exec exécute une requête sans retourner de lignes (implémente query.Executor)
+func (c *Connection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.db.Exec(query, args...)
+}
+
+// Query exécute une requête qui retourne des lignes (implémente query.Executor)
+func (c *Connection) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.Query(query, args...)
+}
+
+// QueryRow exécute une requête qui retourne au plus une ligne (implémente query.Executor)
+func (c *Connection) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRow(query, args...)
+}
+
+// ExecContext exécute une requête sans retourner de lignes (implémente query.Executor)
+func (c *Connection) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+// QueryContext exécute une requête qui retourne des lignes (implémente query.Executor)
+func (c *Connection) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext exécute une requête qui retourne au plus une ligne (implémente query.Executor)
+func (c *Connection) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(ctx, query, args...)
+}
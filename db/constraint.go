@@ -0,0 +1,202 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConstraintKind représente le type d'une contrainte nommée
+type ConstraintKind string
+
+const (
+	CheckConstraint      ConstraintKind = "CHECK"
+	UniqueConstraint     ConstraintKind = "UNIQUE"
+	PrimaryKeyConstraint ConstraintKind = "PRIMARY KEY"
+)
+
+// Constraint représente une contrainte nommée : CHECK d'une colonne ou de la table,
+// UNIQUE ou PRIMARY KEY portant sur une ou plusieurs colonnes
+type Constraint struct {
+	name       string
+	kind       ConstraintKind
+	columns    []string
+	expression string
+}
+
+// Check ajoute à la colonne une contrainte CHECK, nommée <table>_<colonne>_check
+// comme le ferait PostgreSQL (ex: Check("employee_count >= 0"))
+func (ab *AttributeBuilder) Check(expression string) *AttributeBuilder {
+	return ab.CheckNamed(ab.attribute.defaultCheckName(ab.tableBuilder.name), expression)
+}
+
+// CheckNamed ajoute à la colonne une contrainte CHECK portant le nom donné
+// (ex: CheckNamed("positive_price", "price > 0"))
+func (ab *AttributeBuilder) CheckNamed(name, expression string) *AttributeBuilder {
+	ab.attribute.checks = append(ab.attribute.checks, &Constraint{
+		name:       name,
+		kind:       CheckConstraint,
+		columns:    []string{ab.attribute.name},
+		expression: expression,
+	})
+	return ab
+}
+
+// defaultCheckName retourne le nom que Check donnerait au prochain CHECK de la colonne :
+// <table>_<colonne>_check, puis <table>_<colonne>_check1...
+func (a *Attribute) defaultCheckName(tableName string) string {
+	name := fmt.Sprintf("%s_%s_check", tableName, a.name)
+	if len(a.checks) > 0 {
+		name += fmt.Sprint(len(a.checks))
+	}
+	return name
+}
+
+// Check ajoute à la table une contrainte CHECK nommée, qui peut porter sur plusieurs
+// colonnes (ex: Check("posts_dates_check", "updated_at >= created_at"))
+func (tb *TableBuilder) Check(name, expression string) *TableBuilder {
+	tb.constraints = append(tb.constraints, &Constraint{
+		name:       name,
+		kind:       CheckConstraint,
+		expression: expression,
+	})
+	return tb
+}
+
+// Unique ajoute à la table une contrainte UNIQUE sur un ensemble de colonnes,
+// nommée <table>_<colonnes>_key comme le ferait PostgreSQL
+func (tb *TableBuilder) Unique(columns ...string) *TableBuilder {
+	if len(columns) == 0 {
+		panic(fmt.Sprintf("la contrainte UNIQUE de la table %s doit porter sur au moins une colonne", tb.name))
+	}
+	tb.constraints = append(tb.constraints, &Constraint{
+		name:    fmt.Sprintf("%s_%s_key", tb.name, strings.Join(columns, "_")),
+		kind:    UniqueConstraint,
+		columns: columns,
+	})
+	return tb
+}
+
+// PrimaryKey définit une clé primaire naturelle ou composite, nommée <table>_pkey.
+// La colonne id ajoutée par NewTable est retirée si elle ne fait pas partie de la clé,
+// et devient une simple colonne de la clé sinon.
+func (tb *TableBuilder) PrimaryKey(columns ...string) *TableBuilder {
+	if len(columns) == 0 {
+		panic(fmt.Sprintf("la clé primaire de la table %s doit porter sur au moins une colonne", tb.name))
+	}
+	if index := tb.keyIndex(); index >= 0 {
+		if slices.Contains(columns, "id") {
+			id := tb.attributes[index]
+			id.constraints = slices.DeleteFunc(id.constraints, func(constraint string) bool { return constraint == "PRIMARY KEY" })
+		} else {
+			tb.attributes = slices.Delete(tb.attributes, index, index+1)
+		}
+		tb.key = ""
+	}
+	tb.constraints = slices.DeleteFunc(tb.constraints, func(constraint *Constraint) bool {
+		return constraint.kind == PrimaryKeyConstraint
+	})
+	tb.constraints = append(tb.constraints, &Constraint{
+		name:    tb.name + "_pkey",
+		kind:    PrimaryKeyConstraint,
+		columns: columns,
+	})
+	for _, attr := range tb.attributes {
+		attr.inPrimaryKey = slices.Contains(columns, attr.name)
+	}
+	return tb
+}
+
+// buildSQL retourne la définition SQL de la contrainte nommée
+func (c *Constraint) buildSQL() string {
+	return fmt.Sprintf("CONSTRAINT \"%s\" %s", c.name, c.definition())
+}
+
+// definition retourne la définition de la contrainte, sans son nom
+func (c *Constraint) definition() string {
+	if c.kind == CheckConstraint {
+		return fmt.Sprintf("CHECK (%s)", c.expression)
+	}
+	quoted := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		quoted[i] = fmt.Sprintf("\"%s\"", column)
+	}
+	return fmt.Sprintf("%s (%s)", c.kind, strings.Join(quoted, ", "))
+}
+
+// GetName retourne le nom de la contrainte
+func (c *Constraint) GetName() string {
+	return c.name
+}
+
+// GetKind retourne le type de la contrainte
+func (c *Constraint) GetKind() ConstraintKind {
+	return c.kind
+}
+
+// GetColumns retourne les colonnes d'une contrainte UNIQUE ou PRIMARY KEY, ou la colonne
+// d'un CHECK de colonne (vide pour un CHECK de table)
+func (c *Constraint) GetColumns() []string {
+	return c.columns
+}
+
+// GetExpression retourne l'expression d'une contrainte CHECK
+func (c *Constraint) GetExpression() string {
+	return c.expression
+}
+
+// GetChecks retourne les contraintes CHECK déclarées sur la colonne
+func (a *Attribute) GetChecks() []*Constraint {
+	return a.checks
+}
+
+// GetConstraints retourne les contraintes de table (CHECK, UNIQUE et PRIMARY KEY composites)
+func (tb *TableBuilder) GetConstraints() []*Constraint {
+	return tb.constraints
+}
+
+// GetAllConstraints retourne toutes les contraintes nommées de la table : celles
+// de la table puis les CHECK de chaque colonne
+func (tb *TableBuilder) GetAllConstraints() []*Constraint {
+	constraints := slices.Clone(tb.constraints)
+	for _, attr := range tb.attributes {
+		constraints = append(constraints, attr.checks...)
+	}
+	return constraints
+}
+
+// hasConstraint vérifie si la table déclare une contrainte de table portant le nom donné
+func (tb *TableBuilder) hasConstraint(name string) bool {
+	return slices.ContainsFunc(tb.constraints, func(constraint *Constraint) bool {
+		return constraint.name == name
+	})
+}
+
+// isKeyColumn vérifie si la colonne fait partie de la clé primaire déclarée par PrimaryKey
+func (tb *TableBuilder) isKeyColumn(column string) bool {
+	return slices.ContainsFunc(tb.constraints, func(constraint *Constraint) bool {
+		return constraint.kind == PrimaryKeyConstraint && slices.Contains(constraint.columns, column)
+	})
+}
+
+// validateConstraints vérifie que les contraintes et les index de la table portent sur des colonnes déclarées
+func (tb *TableBuilder) validateConstraints() error {
+	declared := func(column string) bool {
+		return slices.ContainsFunc(tb.attributes, func(attr *Attribute) bool { return attr.name == column })
+	}
+	for _, constraint := range tb.constraints {
+		for _, column := range constraint.columns {
+			if !declared(column) {
+				return fmt.Errorf("la contrainte '%s' de la table '%s' porte sur la colonne inconnue '%s'", constraint.name, tb.name, column)
+			}
+		}
+	}
+	for _, index := range tb.indexes {
+		for _, column := range index.columns {
+			if !declared(column) {
+				return fmt.Errorf("l'index '%s' de la table '%s' porte sur la colonne inconnue '%s'", index.name, tb.name, column)
+			}
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -11,10 +12,15 @@ import (
 // Cette fonction vérifie d'abord si la base de données existe déjà avant de la créer
 // pour éviter les erreurs de duplication.
 func (c *Connection) CreateDatabase(dbname string) error {
+	return c.CreateDatabaseContext(context.Background(), dbname)
+}
+
+// CreateDatabaseContext crée une base de données comme CreateDatabase en respectant le contexte
+func (c *Connection) CreateDatabaseContext(ctx context.Context, dbname string) error {
 	// Vérification si la base de données existe déjà
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)`
-	err := c.db.QueryRow(query, dbname).Scan(&exists)
+	err := c.db.QueryRowContext(ctx, query, dbname).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("failed to check if database exists: %w", err)
 	}
@@ -22,7 +28,7 @@ func (c *Connection) CreateDatabase(dbname string) error {
 	// Si la base de données n'existe pas, la créer
 	if !exists {
 		createQuery := fmt.Sprintf("CREATE DATABASE %s", dbname)
-		_, err = c.db.Exec(createQuery)
+		_, err = c.db.ExecContext(ctx, createQuery)
 		if err != nil {
 			return fmt.Errorf("failed to create database %s: %w", dbname, err)
 		}
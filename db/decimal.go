@@ -0,0 +1,43 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// Decimal représente une valeur NUMERIC sous sa forme textuelle exacte (ex: "1234.50").
+// Les montants ne transitent jamais par un float64 et ne perdent donc pas de précision.
+type Decimal string
+
+// String retourne la représentation textuelle de la valeur
+func (d Decimal) String() string {
+	return string(d)
+}
+
+// Float64 convertit la valeur en float64, avec une éventuelle perte de précision
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(string(d), 64)
+}
+
+// Value implémente driver.Valuer : la valeur est transmise sous forme textuelle
+func (d Decimal) Value() (driver.Value, error) {
+	return string(d), nil
+}
+
+// Scan implémente sql.Scanner pour lire une colonne NUMERIC
+func (d *Decimal) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		*d = Decimal(value)
+	case string:
+		*d = Decimal(value)
+	case int64:
+		*d = Decimal(strconv.FormatInt(value, 10))
+	case float64:
+		*d = Decimal(strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		return fmt.Errorf("impossible de convertir %T en Decimal", src)
+	}
+	return nil
+}
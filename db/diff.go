@@ -0,0 +1,903 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"postgo/logging"
+	"slices"
+	"strings"
+)
+
+// ChangeKind indique si un élément doit être ajouté ou supprimé de la base de données
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+)
+
+// SchemaDiff représente les différences entre le schéma déclaré et la base de données
+type SchemaDiff struct {
+	// MissingEnums contient les types énumérés utilisés par le schéma absents de la base
+	MissingEnums []*Enum
+	// EnumValues contient les valeurs déclarées absentes des types énumérés existants
+	EnumValues []EnumValueChange
+	// MissingTables contient les tables déclarées absentes de la base, dans l'ordre de création
+	MissingTables []*TableBuilder
+	// ExtraTables contient les tables présentes en base mais absentes du schéma.
+	// Elles sont signalées mais ne sont jamais supprimées par les instructions générées.
+	ExtraTables []string
+	// Tables contient les différences des tables présentes des deux côtés
+	Tables []*TableDiff
+	// KeyChanges contient les tables dont la stratégie de clé primaire id a changé. Elles sont
+	// signalées mais ne produisent aucune instruction : la conversion d'une clé existante
+	// (séquence, valeurs déjà attribuées, clés étrangères) s'écrit à la main.
+	KeyChanges []KeyChange
+}
+
+// KeyChange représente une table dont la stratégie de clé primaire déclarée (WithKey) diffère
+// de celle de la base. Une stratégie vide désigne une table sans clé id attribuée par PostgreSQL.
+type KeyChange struct {
+	Table string
+	From  KeyStrategy
+	To    KeyStrategy
+}
+
+// TableDiff représente les différences d'une table existante
+type TableDiff struct {
+	Name              string
+	AddedColumns      []*Attribute
+	RemovedColumns    []RemovedColumn
+	TypeChanges       []TypeChange
+	DefaultChanges    []DefaultChange
+	ConstraintChanges []ConstraintChange
+	IndexChanges      []IndexChange
+}
+
+// RemovedColumn représente une colonne présente en base mais absente du schéma
+type RemovedColumn struct {
+	Name string
+	// DataType est le type de la colonne en base, tel que retourné par PostgreSQL
+	DataType string
+}
+
+// TypeChange représente une colonne dont le type déclaré diffère du type en base
+type TypeChange struct {
+	Column string
+	From   string
+	To     AttributeType
+}
+
+// DefaultChange représente une colonne dont la valeur par défaut déclarée diffère de celle en base
+type DefaultChange struct {
+	Column string
+	// From est l'expression DEFAULT en base et To l'expression déclarée, vides sans valeur par défaut
+	From string
+	To   string
+}
+
+// ConstraintChange représente une contrainte de colonne ou de table à ajouter ou à supprimer
+type ConstraintChange struct {
+	// Column est vide pour les contraintes de table (CHECK de table, UNIQUE et PRIMARY KEY composites)
+	Column string
+	Kind   ChangeKind
+	// Constraint vaut "NOT NULL", "UNIQUE", "FOREIGN KEY", "CHECK" ou "PRIMARY KEY"
+	Constraint string
+	// Name est le nom de la contrainte en base (suppression) ou à créer (ajout), vide pour NOT NULL
+	Name string
+	// Reference décrit la clé étrangère ajoutée ou supprimée
+	Reference *ForeignKey
+	// Definition est la définition SQL d'une contrainte nommée, sans son nom
+	// (ex: CHECK (employee_count >= 0), UNIQUE ("company_id", "slug"))
+	Definition string
+}
+
+// IndexChange représente un index à créer ou à supprimer. Un index modifié ou invalide en
+// base est supprimé puis recréé.
+type IndexChange struct {
+	Kind ChangeKind
+	Name string
+	// Index est l'index déclaré à créer, nil pour une suppression
+	Index *Index
+	// Definition est l'instruction de création d'un index supprimé, telle que retournée par
+	// pg_get_indexdef, qui permet de le recréer
+	Definition string
+}
+
+// migrationStatement associe une instruction à l'instruction qui l'annule, vide si elle
+// ne peut pas être annulée
+type migrationStatement struct {
+	up   string
+	down string
+}
+
+// DiffSchema compare les tables enregistrées dans le schéma par défaut avec celles
+// du schéma courant de la base de données
+func DiffSchema(conn *Connection) (*SchemaDiff, error) {
+	return globalSchema.DiffSchema(conn)
+}
+
+// DiffSchemaContext compare le schéma par défaut avec la base de données comme DiffSchema en respectant le contexte
+func DiffSchemaContext(ctx context.Context, conn *Connection) (*SchemaDiff, error) {
+	return globalSchema.DiffSchemaContext(ctx, conn)
+}
+
+// DiffSchema compare les tables enregistrées dans le schéma avec celles
+// du schéma courant de la base de données
+func (s *Schema) DiffSchema(conn *Connection) (*SchemaDiff, error) {
+	return s.DiffSchemaContext(context.Background(), conn)
+}
+
+// DiffSchemaContext compare le schéma avec la base de données comme DiffSchema en respectant le contexte
+func (s *Schema) DiffSchemaContext(ctx context.Context, conn *Connection) (*SchemaDiff, error) {
+	return s.diff(ctx, conn)
+}
+
+// diff compare le schéma avec la base de données
+func (s *Schema) diff(ctx context.Context, conn *Connection) (*SchemaDiff, error) {
+	liveTables, err := readCatalog(ctx, conn, "")
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]*liveTable, len(liveTables))
+	for _, table := range liveTables {
+		live[table.name] = table
+	}
+
+	order, err := s.creationOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	enums, err := s.enums(order)
+	if err != nil {
+		return nil, err
+	}
+	liveEnums, err := readEnums(ctx, conn, "")
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SchemaDiff{}
+	if err := diff.diffEnums(enums, liveEnums); err != nil {
+		return nil, err
+	}
+	for _, tableName := range order {
+		table := s.tables[tableName]
+		liveTable, exists := live[tableName]
+		if !exists {
+			diff.MissingTables = append(diff.MissingTables, table)
+			continue
+		}
+
+		rewritten := rewrites{
+			checks:   conn.normalizeChecks(ctx, table, liveTable),
+			defaults: conn.normalizeDefaults(ctx, table, liveTable),
+			indexes:  conn.normalizeIndexes(ctx, table, liveTable),
+		}
+		if tableDiff := diffTable(table, liveTable, rewritten); !tableDiff.IsEmpty() {
+			diff.Tables = append(diff.Tables, tableDiff)
+		}
+		if from := liveKeyStrategy(liveTable); from != table.key {
+			diff.KeyChanges = append(diff.KeyChanges, KeyChange{Table: tableName, From: from, To: table.key})
+		}
+	}
+
+	for _, table := range liveTables {
+		if _, declared := s.tables[table.name]; !declared && table.name != migrationsTable {
+			diff.ExtraTables = append(diff.ExtraTables, table.name)
+		}
+	}
+
+	return diff, nil
+}
+
+// diffEnums compare les types énumérés du schéma avec ceux de la base, dont live contient
+// les valeurs. Un type existant dont des valeurs ont été retirées ou réordonnées est
+// signalé par une erreur : PostgreSQL ne sait pas le modifier.
+func (d *SchemaDiff) diffEnums(enums []*Enum, live map[string][]string) error {
+	for _, enum := range enums {
+		existing, exists := live[enum.name]
+		if !exists {
+			d.MissingEnums = append(d.MissingEnums, enum)
+			continue
+		}
+
+		values, err := enum.addedValues(existing)
+		if err != nil {
+			return err
+		}
+		d.EnumValues = append(d.EnumValues, values...)
+	}
+	return nil
+}
+
+// rewrites contient des expressions déclarées telles que PostgreSQL les réécrit (parenthèses,
+// conversions explicites...), pour les comparer à celles lues en base
+type rewrites struct {
+	// checks est indexé par nom de contrainte (voir normalizeChecks)
+	checks map[string]string
+	// defaults est indexé par nom de colonne (voir normalizeDefaults)
+	defaults map[string]string
+	// indexes contient la définition des index déclarés, indexée par nom d'index (voir normalizeIndexes)
+	indexes map[string]string
+}
+
+// diffTable compare la déclaration d'une table avec son état en base. rewritten contient les
+// expressions déclarées telles que PostgreSQL les réécrit.
+func diffTable(table *TableBuilder, live *liveTable, rewritten rewrites) *TableDiff {
+	tableDiff := &TableDiff{Name: table.name}
+	declared := make(map[string]bool, len(table.attributes))
+
+	for _, attr := range table.attributes {
+		declared[attr.name] = true
+
+		column := live.column(attr.name)
+		if column == nil {
+			tableDiff.AddedColumns = append(tableDiff.AddedColumns, attr)
+			continue
+		}
+
+		if canonicalType(string(attr.dataType)) != canonicalType(column.dataType) {
+			tableDiff.TypeChanges = append(tableDiff.TypeChanges, TypeChange{
+				Column: attr.name,
+				From:   column.dataType,
+				To:     attr.dataType,
+			})
+		}
+
+		if comparesDefault(attr, column) && !matchesExpression(column.defaultExpr, attr.defaultExpr, rewritten.defaults[attr.name]) {
+			tableDiff.DefaultChanges = append(tableDiff.DefaultChanges, DefaultChange{
+				Column: attr.name,
+				From:   column.defaultExpr,
+				To:     attr.defaultExpr,
+			})
+		}
+
+		tableDiff.ConstraintChanges = append(tableDiff.ConstraintChanges, diffColumnConstraints(table, attr, column, live)...)
+	}
+
+	tableDiff.ConstraintChanges = append(tableDiff.ConstraintChanges, diffNamedConstraints(table, live, tableDiff.AddedColumns, rewritten.checks)...)
+	tableDiff.IndexChanges = diffIndexes(table, live, rewritten.indexes)
+
+	for _, column := range live.columns {
+		if !declared[column.name] {
+			tableDiff.RemovedColumns = append(tableDiff.RemovedColumns, RemovedColumn{
+				Name:     column.name,
+				DataType: column.dataType,
+			})
+		}
+	}
+
+	return tableDiff
+}
+
+// comparesDefault indique si la valeur par défaut de la colonne est comparée : celle d'une
+// colonne SERIAL ou IDENTITY, attribuée par PostgreSQL, et l'expression d'une colonne
+// calculée ne le sont pas
+func comparesDefault(attr *Attribute, column *liveColumn) bool {
+	return !attr.IsAutoIncrement() && attr.generatedExpr == "" && column.identity == "" && !column.generated
+}
+
+// matchesExpression vérifie qu'une expression en base correspond à l'expression déclarée, au
+// texte près ou sinon à sa réécriture par PostgreSQL (rewritten, vide si elle n'a pas pu être obtenue)
+func matchesExpression(live, declared, rewritten string) bool {
+	live = normalizeCheckText(live)
+	return live == normalizeCheckText(declared) || (rewritten != "" && live == normalizeCheckText(rewritten))
+}
+
+// diffColumnConstraints compare les contraintes NOT NULL, UNIQUE et FOREIGN KEY d'une colonne
+func diffColumnConstraints(table *TableBuilder, attr *Attribute, column *liveColumn, live *liveTable) []ConstraintChange {
+	var changes []ConstraintChange
+	tableName := table.name
+
+	// La clé primaire implique NOT NULL, elle n'est pas comparée ici
+	if !attr.IsPrimaryKey() && attr.IsRequired() != column.notNull {
+		kind := ChangeAdded
+		if column.notNull {
+			kind = ChangeRemoved
+		}
+		changes = append(changes, ConstraintChange{Column: attr.name, Kind: kind, Constraint: "NOT NULL"})
+	}
+
+	liveUnique := live.columnConstraint("u", attr.name)
+	switch {
+	case attr.IsUnique() && liveUnique == nil:
+		changes = append(changes, ConstraintChange{
+			Column:     attr.name,
+			Kind:       ChangeAdded,
+			Constraint: "UNIQUE",
+			Name:       fmt.Sprintf("%s_%s_key", tableName, attr.name),
+		})
+	case !attr.IsUnique() && liveUnique != nil && !table.hasConstraint(liveUnique.name):
+		changes = append(changes, ConstraintChange{
+			Column:     attr.name,
+			Kind:       ChangeRemoved,
+			Constraint: "UNIQUE",
+			Name:       liveUnique.name,
+		})
+	}
+
+	liveForeignKey := live.columnConstraint("f", attr.name)
+	if liveForeignKey != nil && !liveForeignKey.matches(attr.reference) {
+		changes = append(changes, ConstraintChange{
+			Column:     attr.name,
+			Kind:       ChangeRemoved,
+			Constraint: "FOREIGN KEY",
+			Name:       liveForeignKey.name,
+			Reference:  liveForeignKey.foreignKey(),
+		})
+	}
+	if attr.reference != nil && (liveForeignKey == nil || !liveForeignKey.matches(attr.reference)) {
+		changes = append(changes, ConstraintChange{
+			Column:     attr.name,
+			Kind:       ChangeAdded,
+			Constraint: "FOREIGN KEY",
+			Name:       fmt.Sprintf("%s_%s_fkey", tableName, attr.name),
+			Reference:  attr.reference,
+		})
+	}
+
+	return changes
+}
+
+// diffNamedConstraints compare par leur nom les contraintes nommées de la table : CHECK de
+// table et de colonne, UNIQUE et PRIMARY KEY composites. Les CHECK des colonnes ajoutées sont
+// créés avec elles. Une contrainte UNIQUE ou PRIMARY KEY dont les colonnes ont changé, ou un
+// CHECK dont l'expression a changé, est supprimée puis recréée.
+func diffNamedConstraints(table *TableBuilder, live *liveTable, addedColumns []*Attribute, checks map[string]string) []ConstraintChange {
+	var changes []ConstraintChange
+	declared := make(map[string]bool)
+
+	for _, constraint := range table.GetAllConstraints() {
+		declared[constraint.name] = true
+		if slices.ContainsFunc(addedColumns, func(attr *Attribute) bool { return slices.Contains(attr.checks, constraint) }) {
+			continue
+		}
+
+		liveConstraint := live.constraintNamed(constraint.name)
+		if liveConstraint != nil && liveConstraint.matchesConstraint(constraint, checks[constraint.name]) {
+			continue
+		}
+		if liveConstraint != nil {
+			changes = append(changes, liveConstraint.removal())
+		}
+
+		change := ConstraintChange{
+			Kind:       ChangeAdded,
+			Constraint: string(constraint.kind),
+			Name:       constraint.name,
+			Definition: constraint.definition(),
+		}
+		if constraint.kind == CheckConstraint && len(constraint.columns) == 1 {
+			change.Column = constraint.columns[0]
+		}
+		changes = append(changes, change)
+	}
+
+	for _, constraint := range live.constraints {
+		if declared[constraint.name] {
+			continue
+		}
+		if constraint.kind == "c" || ((constraint.kind == "u" || constraint.kind == "p") && len(constraint.columns) > 1) {
+			changes = append(changes, constraint.removal())
+		}
+	}
+
+	return changes
+}
+
+// diffIndexes compare par leur nom les index déclarés sur la table avec ceux en base, hors
+// index portant une contrainte. definitions contient la définition des index déclarés telle
+// que PostgreSQL la donne (voir normalizeIndexes).
+func diffIndexes(table *TableBuilder, live *liveTable, definitions map[string]string) []IndexChange {
+	var changes []IndexChange
+	declared := make(map[string]bool, len(table.indexes))
+
+	for _, index := range table.indexes {
+		declared[index.name] = true
+
+		liveIndex := live.index(index.name)
+		if liveIndex != nil && liveIndex.matches(index, definitions[index.name]) {
+			continue
+		}
+		if liveIndex != nil {
+			changes = append(changes, liveIndex.removal())
+		}
+		changes = append(changes, IndexChange{Kind: ChangeAdded, Name: index.name, Index: index})
+	}
+
+	for _, index := range live.indexes {
+		if !declared[index.name] {
+			changes = append(changes, index.removal())
+		}
+	}
+
+	return changes
+}
+
+// matches vérifie que l'index en base, valide, correspond à la déclaration. Une expression ou
+// un prédicat est comparé au texte près, ou sinon par la définition que PostgreSQL donne à
+// l'index déclaré (definition, vide si elle n'a pas pu être obtenue).
+func (l *liveIndex) matches(index *Index, definition string) bool {
+	if !l.valid || l.included || l.ordered {
+		return false
+	}
+	if definition != "" && indexSignature(l.definition) == indexSignature(definition) {
+		return true
+	}
+
+	method := index.method
+	if method == "" {
+		method = BTree
+	}
+	if l.unique != index.unique || l.method != string(method) || !matchesExpression(l.predicate, index.where, "") {
+		return false
+	}
+	if index.expression != "" {
+		return slices.Equal(l.columns, []string{""}) && matchesExpression(l.expression, index.expression, "")
+	}
+	return slices.Equal(l.columns, index.columns)
+}
+
+// indexSignature retourne ce qui caractérise une définition retournée par pg_get_indexdef,
+// sans le nom de l'index ni celui de la table (ex: "UNIQUE USING btree (lower(email))")
+func indexSignature(definition string) string {
+	signature := definition
+	if using := strings.Index(definition, " USING "); using >= 0 {
+		signature = definition[using+1:]
+	}
+	if strings.HasPrefix(definition, "CREATE UNIQUE ") {
+		signature = "UNIQUE " + signature
+	}
+	return signature
+}
+
+// removal construit la suppression d'un index en base, annulable grâce à sa définition
+func (l *liveIndex) removal() IndexChange {
+	return IndexChange{Kind: ChangeRemoved, Name: l.name, Definition: l.definition}
+}
+
+// matchesConstraint vérifie que la contrainte en base correspond à la déclaration. Une
+// expression CHECK est comparée à celle de la base au texte près, ou sinon à sa réécriture
+// par PostgreSQL (normalized, vide si elle n'a pas pu être obtenue).
+func (c *liveConstraint) matchesConstraint(constraint *Constraint, normalized string) bool {
+	switch constraint.kind {
+	case CheckConstraint:
+		if c.kind != "c" {
+			return false
+		}
+		return matchesExpression(checkExpression(c.definition), constraint.expression, normalized)
+	case UniqueConstraint:
+		return c.kind == "u" && slices.Equal(c.columns, constraint.columns)
+	default:
+		return c.kind == "p" && slices.Equal(c.columns, constraint.columns)
+	}
+}
+
+// normalizeChecks retourne, pour chaque CHECK déclaré dont le texte diffère de celui de la
+// contrainte de même nom en base, l'expression telle que PostgreSQL la réécrit (parenthèses,
+// conversions explicites, IN en = ANY...). L'expression est ajoutée à une copie temporaire
+// de la table (voir rewrite). Une expression qui ne peut pas être réécrite
+// (colonne pas encore créée, expression invalide) est absente du résultat : la contrainte
+// est alors recréée.
+func (c *Connection) normalizeChecks(ctx context.Context, table *TableBuilder, live *liveTable) map[string]string {
+	checks := make(map[string]string)
+	for _, constraint := range table.GetAllConstraints() {
+		liveConstraint := live.constraintNamed(constraint.name)
+		if constraint.kind != CheckConstraint || liveConstraint == nil || liveConstraint.kind != "c" ||
+			normalizeCheckText(checkExpression(liveConstraint.definition)) == normalizeCheckText(constraint.expression) {
+			continue
+		}
+
+		definition, err := c.rewrite(ctx, table.name,
+			fmt.Sprintf("ALTER TABLE \"%s\" ADD CONSTRAINT \"postgo_check\" CHECK (%s) NOT VALID", rewriteTable, constraint.expression),
+			"SELECT pg_get_constraintdef(oid) FROM pg_constraint WHERE conrelid = 'pg_temp."+rewriteTable+"'::regclass AND conname = 'postgo_check'")
+		if err != nil {
+			logging.Warning.Printf("Expression de la contrainte '%s' non réécrite par PostgreSQL, comparée au texte près: %v", constraint.name, err)
+			continue
+		}
+		checks[constraint.name] = checkExpression(definition)
+	}
+	return checks
+}
+
+// normalizeDefaults retourne, pour chaque colonne dont la valeur par défaut déclarée diffère
+// au texte près de celle en base, l'expression telle que PostgreSQL la réécrit (ex: 'draft'
+// devient 'draft'::text). Comme pour normalizeChecks, une expression qui ne peut pas être
+// réécrite est absente du résultat.
+func (c *Connection) normalizeDefaults(ctx context.Context, table *TableBuilder, live *liveTable) map[string]string {
+	defaults := make(map[string]string)
+	for _, attr := range table.attributes {
+		column := live.column(attr.name)
+		if column == nil || attr.defaultExpr == "" || !comparesDefault(attr, column) ||
+			normalizeCheckText(column.defaultExpr) == normalizeCheckText(attr.defaultExpr) {
+			continue
+		}
+
+		expression, err := c.rewrite(ctx, table.name,
+			fmt.Sprintf("ALTER TABLE \"%s\" ALTER COLUMN \"%s\" SET DEFAULT %s", rewriteTable, attr.name, attr.defaultExpr),
+			"SELECT pg_get_expr(d.adbin, d.adrelid) FROM pg_attrdef d JOIN pg_attribute a ON a.attrelid = d.adrelid AND a.attnum = d.adnum "+
+				"WHERE d.adrelid = 'pg_temp."+rewriteTable+"'::regclass AND a.attname = $1", attr.name)
+		if err != nil {
+			logging.Warning.Printf("Valeur par défaut de la colonne '%s.%s' non réécrite par PostgreSQL, comparée au texte près: %v", table.name, attr.name, err)
+			continue
+		}
+		defaults[attr.name] = expression
+	}
+	return defaults
+}
+
+// normalizeIndexes retourne, pour chaque index déclaré sur une expression ou partiel dont
+// l'expression ou le prédicat diffère au texte près de l'index en base de même nom, sa
+// définition telle que pg_get_indexdef la donnerait. L'index est créé sur une copie temporaire
+// de la table (voir rewrite) ; un index qui ne peut pas y être créé est absent du résultat.
+func (c *Connection) normalizeIndexes(ctx context.Context, table *TableBuilder, live *liveTable) map[string]string {
+	definitions := make(map[string]string)
+	for _, index := range table.indexes {
+		liveIndex := live.index(index.name)
+		if liveIndex == nil || (index.expression == "" && index.where == "") || liveIndex.matches(index, "") {
+			continue
+		}
+
+		rewritten := *index
+		rewritten.name, rewritten.table = rewriteTable+"_idx", rewriteTable
+		definition, err := c.rewrite(ctx, table.name, rewritten.BuildSQL(),
+			"SELECT pg_get_indexdef('pg_temp."+rewritten.name+"'::regclass)")
+		if err != nil {
+			logging.Warning.Printf("Définition de l'index '%s' non réécrite par PostgreSQL, comparée au texte près: %v", index.name, err)
+			continue
+		}
+		definitions[index.name] = definition
+	}
+	return definitions
+}
+
+// rewriteTable est le nom de la copie temporaire d'une table utilisée par rewrite
+const rewriteTable = "postgo_rewrite"
+
+// rewrite exécute statement sur une copie temporaire et vide de la table, nommée rewriteTable,
+// puis retourne le résultat de query : une définition telle que PostgreSQL la réécrit. La
+// transaction est annulée, la base de données n'est pas modifiée.
+func (c *Connection) rewrite(ctx context.Context, tableName, statement, query string, args ...interface{}) (string, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		fmt.Sprintf("CREATE TEMPORARY TABLE \"%s\" (LIKE \"%s\") ON COMMIT DROP", rewriteTable, tableName),
+		statement,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return "", err
+		}
+	}
+
+	var definition string
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&definition)
+	return definition, err
+}
+
+// normalizeCheckText normalise le texte d'une expression CHECK : espaces consécutifs
+// réduits et parenthèses englobant toute l'expression retirées
+func normalizeCheckText(expression string) string {
+	expression = strings.Join(strings.Fields(expression), " ")
+	for strings.HasPrefix(expression, "(") && closingParenthesis(expression) == len(expression)-1 {
+		expression = strings.TrimSpace(expression[1 : len(expression)-1])
+	}
+	return expression
+}
+
+// closingParenthesis retourne la position de la parenthèse fermant celle ouvrant l'expression,
+// en ignorant celles des chaînes littérales, ou -1
+func closingParenthesis(expression string) int {
+	depth, quoted := 0, false
+	for i, r := range expression {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// removal construit la suppression d'une contrainte nommée en base, annulable grâce à sa définition
+func (c *liveConstraint) removal() ConstraintChange {
+	constraint := map[string]string{"c": "CHECK", "u": "UNIQUE", "p": "PRIMARY KEY", "f": "FOREIGN KEY"}[c.kind]
+	return ConstraintChange{
+		Kind:       ChangeRemoved,
+		Constraint: constraint,
+		Name:       c.name,
+		Definition: c.definition,
+	}
+}
+
+// matches vérifie que la clé étrangère en base correspond à la déclaration
+func (c *liveConstraint) matches(fk *ForeignKey) bool {
+	return fk != nil &&
+		len(c.referencedColumns) == 1 &&
+		c.referencedTable == fk.table &&
+		c.referencedColumns[0] == fk.column &&
+		c.onDelete == fk.onDelete &&
+		c.onUpdate == fk.onUpdate
+}
+
+// foreignKey convertit une contrainte FOREIGN KEY mono-colonne en ForeignKey
+func (c *liveConstraint) foreignKey() *ForeignKey {
+	fk := &ForeignKey{
+		table:    c.referencedTable,
+		onDelete: c.onDelete,
+		onUpdate: c.onUpdate,
+	}
+	if len(c.referencedColumns) > 0 {
+		fk.column = c.referencedColumns[0]
+	}
+	return fk
+}
+
+// IsEmpty indique si la base de données correspond au schéma déclaré.
+// Les tables supplémentaires et les changements de clé primaire ne sont pas pris en compte.
+func (d *SchemaDiff) IsEmpty() bool {
+	return len(d.MissingEnums) == 0 && len(d.EnumValues) == 0 && len(d.MissingTables) == 0 && len(d.Tables) == 0
+}
+
+// IsEmpty indique si la table ne présente aucune différence
+func (d *TableDiff) IsEmpty() bool {
+	return len(d.AddedColumns) == 0 &&
+		len(d.RemovedColumns) == 0 &&
+		len(d.TypeChanges) == 0 &&
+		len(d.DefaultChanges) == 0 &&
+		len(d.ConstraintChanges) == 0 &&
+		len(d.IndexChanges) == 0
+}
+
+// Statements retourne les instructions SQL qui alignent la base de données sur le schéma
+func (d *SchemaDiff) Statements() []string {
+	var statements []string
+	for _, statement := range d.statements() {
+		statements = append(statements, statement.up)
+	}
+	return statements
+}
+
+// RollbackStatements retourne les instructions SQL qui annulent Statements, dans l'ordre inverse.
+// Les données des colonnes et tables supprimées ne peuvent pas être restaurées, ni les valeurs
+// ajoutées à un type énuméré être retirées.
+func (d *SchemaDiff) RollbackStatements() []string {
+	statements := d.statements()
+	rollback := make([]string, 0, len(statements))
+	for i := len(statements) - 1; i >= 0; i-- {
+		if statements[i].down != "" {
+			rollback = append(rollback, statements[i].down)
+		}
+	}
+	return rollback
+}
+
+// ToMigration convertit les différences en migration versionnée
+func (d *SchemaDiff) ToMigration(version int64, name string) Migration {
+	return Migration{
+		Version: version,
+		Name:    name,
+		Up:      joinStatements(d.Statements()),
+		Down:    joinStatements(d.RollbackStatements()),
+	}
+}
+
+// statements construit les paires d'instructions d'application et d'annulation
+func (d *SchemaDiff) statements() []migrationStatement {
+	var statements []migrationStatement
+
+	// Les types énumérés doivent exister avant les colonnes qui les utilisent
+	for _, enum := range d.MissingEnums {
+		statements = append(statements, migrationStatement{
+			up:   enum.createSQL(),
+			down: fmt.Sprintf("DROP TYPE \"%s\"", enum.name),
+		})
+	}
+	for _, value := range d.EnumValues {
+		statements = append(statements, migrationStatement{up: value.sql()})
+	}
+
+	for _, table := range d.MissingTables {
+		statements = append(statements, migrationStatement{
+			up:   table.BuildSQL(),
+			down: fmt.Sprintf("DROP TABLE \"%s\"", table.name),
+		})
+		// Les index disparaissent avec la table lors de l'annulation
+		for _, index := range table.indexes {
+			statements = append(statements, migrationStatement{up: index.BuildSQL()})
+		}
+	}
+
+	for _, table := range d.Tables {
+		statements = append(statements, table.statements()...)
+	}
+
+	return statements
+}
+
+// statements construit les instructions ALTER TABLE de la table
+func (d *TableDiff) statements() []migrationStatement {
+	var statements []migrationStatement
+	alter := fmt.Sprintf("ALTER TABLE \"%s\" ", d.Name)
+
+	for _, attr := range d.AddedColumns {
+		statements = append(statements, migrationStatement{
+			up:   alter + "ADD COLUMN " + attr.buildSQL(),
+			down: alter + fmt.Sprintf("DROP COLUMN \"%s\"", attr.name),
+		})
+	}
+
+	// Un index supprimé peut porter sur une colonne dont le type change
+	for _, change := range d.IndexChanges {
+		if change.Kind == ChangeRemoved {
+			statements = append(statements, migrationStatement{
+				up:   fmt.Sprintf("DROP INDEX \"%s\"", change.Name),
+				down: change.Definition,
+			})
+		}
+	}
+
+	for _, change := range d.TypeChanges {
+		statements = append(statements, migrationStatement{
+			up:   alter + fmt.Sprintf("ALTER COLUMN \"%s\" TYPE %s USING \"%s\"::%s", change.Column, change.To, change.Column, change.To),
+			down: alter + fmt.Sprintf("ALTER COLUMN \"%s\" TYPE %s USING \"%s\"::%s", change.Column, change.From, change.Column, change.From),
+		})
+	}
+
+	for _, change := range d.DefaultChanges {
+		statements = append(statements, migrationStatement{
+			up:   alter + defaultSQL(change.Column, change.To),
+			down: alter + defaultSQL(change.Column, change.From),
+		})
+	}
+
+	// Les suppressions de contraintes précèdent les ajouts pour permettre le remplacement d'une clé étrangère
+	for _, kind := range []ChangeKind{ChangeRemoved, ChangeAdded} {
+		for _, change := range d.ConstraintChanges {
+			if change.Kind != kind {
+				continue
+			}
+			add, drop := change.addSQL(), change.dropSQL()
+			if kind == ChangeAdded {
+				statements = append(statements, migrationStatement{up: alter + add, down: alter + drop})
+			} else {
+				statements = append(statements, migrationStatement{up: alter + drop, down: alter + add})
+			}
+		}
+	}
+
+	for _, change := range d.IndexChanges {
+		if change.Kind == ChangeAdded {
+			statements = append(statements, migrationStatement{
+				up:   change.Index.BuildSQL(),
+				down: fmt.Sprintf("DROP INDEX \"%s\"", change.Name),
+			})
+		}
+	}
+
+	for _, column := range d.RemovedColumns {
+		statements = append(statements, migrationStatement{
+			up:   alter + fmt.Sprintf("DROP COLUMN \"%s\"", column.Name),
+			down: alter + fmt.Sprintf("ADD COLUMN \"%s\" %s", column.Name, column.DataType),
+		})
+	}
+
+	return statements
+}
+
+// defaultSQL retourne la clause ALTER TABLE qui donne à la colonne la valeur par défaut
+// expression, ou qui la supprime si expression est vide
+func defaultSQL(column, expression string) string {
+	if expression == "" {
+		return fmt.Sprintf("ALTER COLUMN \"%s\" DROP DEFAULT", column)
+	}
+	return fmt.Sprintf("ALTER COLUMN \"%s\" SET DEFAULT %s", column, expression)
+}
+
+// addSQL retourne la clause ALTER TABLE qui crée la contrainte
+func (c ConstraintChange) addSQL() string {
+	if c.Definition != "" {
+		return fmt.Sprintf("ADD CONSTRAINT \"%s\" %s", c.Name, c.Definition)
+	}
+
+	switch c.Constraint {
+	case "NOT NULL":
+		return fmt.Sprintf("ALTER COLUMN \"%s\" SET NOT NULL", c.Column)
+	case "FOREIGN KEY":
+		return fmt.Sprintf("ADD CONSTRAINT \"%s\" FOREIGN KEY (\"%s\") %s", c.Name, c.Column, c.Reference.buildSQL())
+	default:
+		return fmt.Sprintf("ADD CONSTRAINT \"%s\" %s (\"%s\")", c.Name, c.Constraint, c.Column)
+	}
+}
+
+// dropSQL retourne la clause ALTER TABLE qui supprime la contrainte
+func (c ConstraintChange) dropSQL() string {
+	if c.Constraint == "NOT NULL" {
+		return fmt.Sprintf("ALTER COLUMN \"%s\" DROP NOT NULL", c.Column)
+	}
+	return fmt.Sprintf("DROP CONSTRAINT \"%s\"", c.Name)
+}
+
+// joinStatements assemble des instructions SQL en un script
+func joinStatements(statements []string) string {
+	if len(statements) == 0 {
+		return ""
+	}
+	return strings.Join(statements, ";\n") + ";\n"
+}
+
+// canonicalTypeNames associe les noms de types SQL aux noms retournés par format_type
+var canonicalTypeNames = map[string]string{
+	"VARCHAR":                     "character varying",
+	"CHARACTER VARYING":           "character varying",
+	"CHAR":                        "character",
+	"CHARACTER":                   "character",
+	"TEXT":                        "text",
+	"SMALLINT":                    "smallint",
+	"INT2":                        "smallint",
+	"SMALLSERIAL":                 "smallint",
+	"INT":                         "integer",
+	"INT4":                        "integer",
+	"INTEGER":                     "integer",
+	"SERIAL":                      "integer",
+	"BIGINT":                      "bigint",
+	"INT8":                        "bigint",
+	"BIGSERIAL":                   "bigint",
+	"REAL":                        "real",
+	"FLOAT4":                      "real",
+	"FLOAT":                       "double precision",
+	"FLOAT8":                      "double precision",
+	"DOUBLE PRECISION":            "double precision",
+	"NUMERIC":                     "numeric",
+	"DECIMAL":                     "numeric",
+	"BOOL":                        "boolean",
+	"BOOLEAN":                     "boolean",
+	"DATE":                        "date",
+	"TIME":                        "time without time zone",
+	"TIME WITHOUT TIME ZONE":      "time without time zone",
+	"TIMETZ":                      "time with time zone",
+	"TIME WITH TIME ZONE":         "time with time zone",
+	"TIMESTAMP":                   "timestamp without time zone",
+	"TIMESTAMP WITHOUT TIME ZONE": "timestamp without time zone",
+	"TIMESTAMPTZ":                 "timestamp with time zone",
+	"TIMESTAMP WITH TIME ZONE":    "timestamp with time zone",
+	"UUID":                        "uuid",
+	"JSON":                        "json",
+	"JSONB":                       "jsonb",
+	"BYTEA":                       "bytea",
+}
+
+// canonicalType normalise un type SQL sous la forme retournée par format_type
+// afin de comparer un AttributeType avec le type d'une colonne en base
+// (ex: "VARCHAR(255)" et "character varying(255)", "SERIAL" et "integer")
+func canonicalType(dataType string) string {
+	dataType = strings.TrimSpace(dataType)
+	if strings.HasSuffix(dataType, "[]") {
+		return canonicalType(strings.TrimSuffix(dataType, "[]")) + "[]"
+	}
+
+	base, params := dataType, ""
+	if open := strings.Index(dataType, "("); open >= 0 {
+		base = strings.TrimSpace(dataType[:open])
+		params = strings.ReplaceAll(dataType[open:], " ", "")
+	}
+
+	if name, known := canonicalTypeNames[strings.ToUpper(base)]; known {
+		return name + params
+	}
+	return strings.ToLower(strings.Trim(base, "\"")) + params
+}
@@ -0,0 +1,364 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCheckText(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"employee_count >= 0", "employee_count >= 0"},
+		{"((employee_count >= 0))", "employee_count >= 0"},
+		{"( price  >\n 0 )", "price > 0"},
+		{"(a > 0) AND (b > 0)", "(a > 0) AND (b > 0)"},
+		{"(name <> ')(')", "name <> ')('"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if got := normalizeCheckText(tt.expression); got != tt.want {
+				t.Errorf("normalizeCheckText(%q) = %q, attendu %q", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffNamedConstraintsChecks(t *testing.T) {
+	live := &liveTable{
+		name: "products",
+		constraints: []*liveConstraint{
+			{name: "products_price_check", kind: "c", columns: []string{"price"}, definition: "CHECK ((price > (0)::numeric))"},
+			{name: "products_stock_check", kind: "c", columns: []string{"stock"}, definition: "CHECK ((stock >= 0))"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		stock  string
+		price  string
+		checks map[string]string
+		want   []string
+	}{
+		{"même texte", "stock >= 0", "price > (0)::numeric", nil, nil},
+		{"réécriture identique", "stock >= 0", "price > 0", map[string]string{"products_price_check": "(price > (0)::numeric)"}, nil},
+		{
+			name:   "expression modifiée",
+			stock:  "stock >= 10",
+			price:  "price > 0",
+			checks: map[string]string{"products_price_check": "(price > (0)::numeric)", "products_stock_check": "(stock >= 10)"},
+			want: []string{
+				`removed DROP CONSTRAINT "products_stock_check"`,
+				`added ADD CONSTRAINT "products_stock_check" CHECK (stock >= 10)`,
+			},
+		},
+		{
+			name:  "réécriture indisponible",
+			stock: "stock >= 0",
+			price: "price > 0",
+			want: []string{
+				`removed DROP CONSTRAINT "products_price_check"`,
+				`added ADD CONSTRAINT "products_price_check" CHECK (price > 0)`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := NewTable("products").
+				AddAttribute("price", Numeric).Check(tt.price).Build().
+				AddAttribute("stock", Integer).Check(tt.stock).Build()
+
+			var got []string
+			for _, change := range diffNamedConstraints(table, live, nil, tt.checks) {
+				statement := change.addSQL()
+				if change.Kind == ChangeRemoved {
+					statement = change.dropSQL()
+				}
+				got = append(got, string(change.Kind)+" "+statement)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changements = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalType(t *testing.T) {
+	tests := []struct {
+		dataType string
+		want     string
+	}{
+		{"VARCHAR(255)", "character varying(255)"},
+		{"character varying(255)", "character varying(255)"},
+		{"NUMERIC(15, 2)", "numeric(15,2)"},
+		{"SERIAL", "integer"},
+		{"BIGSERIAL", "bigint"},
+		{"int8", "bigint"},
+		{"TIMESTAMPTZ", "timestamp with time zone"},
+		{"TIMESTAMP", "timestamp without time zone"},
+		{"FLOAT", "double precision"},
+		{"TEXT[]", "text[]"},
+		{"VARCHAR(20)[]", "character varying(20)[]"},
+		{"\"Post_Status\"", "post_status"},
+		{"  BOOLEAN ", "boolean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dataType, func(t *testing.T) {
+			if got := canonicalType(tt.dataType); got != tt.want {
+				t.Errorf("canonicalType(%q) = %q, attendu %q", tt.dataType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffStatements(t *testing.T) {
+	users := NewTable("users").
+		AddAttribute("email", Varchar(255)).NotNull().Unique().Build().
+		AddAttribute("bio", Text).Build().
+		AddAttribute("age", BigInt).Check("age >= 0").Build()
+	live := &liveTable{
+		name: "users",
+		columns: []*liveColumn{
+			{name: "id", dataType: "integer", notNull: true},
+			{name: "email", dataType: "character varying(255)"},
+			{name: "age", dataType: "integer"},
+			{name: "legacy", dataType: "text"},
+		},
+		constraints: []*liveConstraint{
+			{name: "users_pkey", kind: "p", columns: []string{"id"}},
+			{name: "users_legacy_check", kind: "c", columns: []string{"legacy"}, definition: "CHECK ((legacy <> ''::text))"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		diff         *SchemaDiff
+		wantUp       []string
+		wantRollback []string
+	}{
+		{
+			name: "table manquante",
+			diff: &SchemaDiff{MissingTables: []*TableBuilder{NewTable("tags").AddAttribute("label", Text).NotNull().Build().AddIndex("label").Build()}},
+			wantUp: []string{
+				`CREATE TABLE IF NOT EXISTS "tags" ("id" SERIAL PRIMARY KEY, "label" TEXT NOT NULL)`,
+				`CREATE INDEX IF NOT EXISTS "tags_label_idx" ON "tags" ("label")`,
+			},
+			wantRollback: []string{`DROP TABLE "tags"`},
+		},
+		{
+			name: "table modifiée",
+			diff: &SchemaDiff{Tables: []*TableDiff{diffTable(users, live, rewrites{})}},
+			wantUp: []string{
+				`ALTER TABLE "users" ADD COLUMN "bio" TEXT`,
+				`ALTER TABLE "users" ALTER COLUMN "age" TYPE BIGINT USING "age"::BIGINT`,
+				`ALTER TABLE "users" DROP CONSTRAINT "users_legacy_check"`,
+				`ALTER TABLE "users" ALTER COLUMN "email" SET NOT NULL`,
+				`ALTER TABLE "users" ADD CONSTRAINT "users_email_key" UNIQUE ("email")`,
+				`ALTER TABLE "users" ADD CONSTRAINT "users_age_check" CHECK (age >= 0)`,
+				`ALTER TABLE "users" DROP COLUMN "legacy"`,
+			},
+			wantRollback: []string{
+				`ALTER TABLE "users" ADD COLUMN "legacy" text`,
+				`ALTER TABLE "users" DROP CONSTRAINT "users_age_check"`,
+				`ALTER TABLE "users" DROP CONSTRAINT "users_email_key"`,
+				`ALTER TABLE "users" ALTER COLUMN "email" DROP NOT NULL`,
+				`ALTER TABLE "users" ADD CONSTRAINT "users_legacy_check" CHECK ((legacy <> ''::text))`,
+				`ALTER TABLE "users" ALTER COLUMN "age" TYPE integer USING "age"::integer`,
+				`ALTER TABLE "users" DROP COLUMN "bio"`,
+			},
+		},
+		{
+			name: "types énumérés",
+			diff: &SchemaDiff{
+				MissingEnums: []*Enum{NewEnum("priority", "low", "high")},
+				EnumValues:   []EnumValueChange{{Enum: "post_status", Value: "archived", After: "published"}},
+			},
+			wantUp: []string{
+				`CREATE TYPE "priority" AS ENUM ('low', 'high')`,
+				`ALTER TYPE "post_status" ADD VALUE IF NOT EXISTS 'archived' AFTER 'published'`,
+			},
+			wantRollback: []string{`DROP TYPE "priority"`},
+		},
+		{
+			name: "aucune différence",
+			diff: &SchemaDiff{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diff.Statements(); !reflect.DeepEqual(got, tt.wantUp) {
+				t.Errorf("Statements =\n%s\nattendu\n%s", strings.Join(got, "\n"), strings.Join(tt.wantUp, "\n"))
+			}
+			rollback := tt.diff.RollbackStatements()
+			if len(rollback) == 0 {
+				rollback = nil
+			}
+			if !reflect.DeepEqual(rollback, tt.wantRollback) {
+				t.Errorf("RollbackStatements =\n%s\nattendu\n%s", strings.Join(rollback, "\n"), strings.Join(tt.wantRollback, "\n"))
+			}
+			if tt.diff.IsEmpty() != (len(tt.wantUp) == 0) {
+				t.Errorf("IsEmpty = %v", tt.diff.IsEmpty())
+			}
+		})
+	}
+}
+
+func TestToMigration(t *testing.T) {
+	diff := &SchemaDiff{MissingTables: []*TableBuilder{NewTable("tags").WithoutKey().AddAttribute("label", Text).Build()}}
+	migration := diff.ToMigration(3, "add_tags")
+
+	wantUp := "CREATE TABLE IF NOT EXISTS \"tags\" (\"label\" TEXT);\n"
+	if migration.Version != 3 || migration.Name != "add_tags" || migration.Up != wantUp || migration.Down != "DROP TABLE \"tags\";\n" {
+		t.Errorf("migration = %+v", migration)
+	}
+	if empty := (&SchemaDiff{}).ToMigration(4, "noop"); empty.Up != "" || empty.Down != "" {
+		t.Errorf("migration vide = %+v", empty)
+	}
+}
+
+func TestDiffDefaults(t *testing.T) {
+	posts := NewTable("posts").
+		AddAttribute("status", Text).Default("draft").Build().
+		AddAttribute("views", Integer).Default(0).Build().
+		AddAttribute("title", Text).Build().
+		AddAttribute("slug", Text).GeneratedAlwaysAs("lower(title)").Build()
+	live := &liveTable{
+		name: "posts",
+		columns: []*liveColumn{
+			{name: "id", dataType: "integer", notNull: true, defaultExpr: "nextval('posts_id_seq'::regclass)"},
+			{name: "status", dataType: "text", defaultExpr: "'draft'::text"},
+			{name: "views", dataType: "integer", defaultExpr: "1"},
+			{name: "title", dataType: "text", defaultExpr: "'sans titre'::text"},
+			{name: "slug", dataType: "text", defaultExpr: "lower(title)", generated: true},
+		},
+		constraints: []*liveConstraint{{name: "posts_pkey", kind: "p", columns: []string{"id"}}},
+	}
+
+	tests := []struct {
+		name     string
+		defaults map[string]string
+		want     []DefaultChange
+	}{
+		{
+			name: "réécriture indisponible",
+			want: []DefaultChange{
+				{Column: "status", From: "'draft'::text", To: "'draft'"},
+				{Column: "views", From: "1", To: "0"},
+				{Column: "title", From: "'sans titre'::text"},
+			},
+		},
+		{
+			name:     "réécriture identique",
+			defaults: map[string]string{"status": "'draft'::text"},
+			want: []DefaultChange{
+				{Column: "views", From: "1", To: "0"},
+				{Column: "title", From: "'sans titre'::text"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffTable(posts, live, rewrites{defaults: tt.defaults}).DefaultChanges
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DefaultChanges = %+v, attendu %+v", got, tt.want)
+			}
+		})
+	}
+
+	statements := (&SchemaDiff{Tables: []*TableDiff{{Name: "posts", DefaultChanges: tests[1].want}}}).Statements()
+	want := []string{
+		`ALTER TABLE "posts" ALTER COLUMN "views" SET DEFAULT 0`,
+		`ALTER TABLE "posts" ALTER COLUMN "title" DROP DEFAULT`,
+	}
+	if !reflect.DeepEqual(statements, want) {
+		t.Errorf("Statements = %q, attendu %q", statements, want)
+	}
+}
+
+func TestDiffIndexes(t *testing.T) {
+	posts := NewTable("posts").
+		AddAttribute("title", Text).Build().
+		AddAttribute("published", Boolean).Build().
+		AddIndex("published").Build().
+		AddExpressionIndex("posts_title_lower_idx", "lower(title)").Build().
+		AddIndex("title").Where("published").Name("posts_published_title_idx").Build()
+	indexes := func(titleDefinition string, valid bool) []*liveIndex {
+		return []*liveIndex{
+			{name: "posts_published_idx", method: "btree", columns: []string{"published"}, valid: valid,
+				definition: "CREATE INDEX posts_published_idx ON public.posts USING btree (published)"},
+			{name: "posts_title_lower_idx", method: "btree", columns: []string{""}, expression: "lower(title)", valid: true,
+				definition: "CREATE INDEX posts_title_lower_idx ON public.posts USING btree (lower(title))"},
+			{name: "posts_published_title_idx", method: "btree", columns: []string{"title"}, predicate: "published", valid: true,
+				definition: titleDefinition},
+			{name: "posts_legacy_idx", method: "hash", columns: []string{"title"}, valid: true,
+				definition: "CREATE INDEX posts_legacy_idx ON public.posts USING hash (title)"},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		live        []*liveIndex
+		definitions map[string]string
+		want        []string
+	}{
+		{
+			name: "index en trop",
+			live: indexes("CREATE INDEX posts_published_title_idx ON public.posts USING btree (title) WHERE published", true),
+			want: []string{
+				`DROP INDEX "posts_legacy_idx"`,
+			},
+		},
+		{
+			name: "index invalide et index manquant",
+			live: indexes("", false)[:2],
+			want: []string{
+				`DROP INDEX "posts_published_idx"`,
+				`CREATE INDEX IF NOT EXISTS "posts_published_idx" ON "posts" ("published")`,
+				`CREATE INDEX IF NOT EXISTS "posts_published_title_idx" ON "posts" ("title") WHERE published`,
+			},
+		},
+		{
+			name: "prédicat réécrit identique",
+			live: func() []*liveIndex {
+				live := indexes("CREATE INDEX posts_published_title_idx ON public.posts USING btree (title) WHERE (published IS TRUE)", true)[:3]
+				live[2].predicate = "(published IS TRUE)"
+				return live
+			}(),
+			definitions: map[string]string{
+				"posts_published_title_idx": "CREATE INDEX postgo_rewrite_idx ON pg_temp_3.postgo_rewrite USING btree (title) WHERE (published IS TRUE)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := &liveTable{
+				name: "posts",
+				columns: []*liveColumn{
+					{name: "id", dataType: "integer", notNull: true, defaultExpr: "nextval('posts_id_seq'::regclass)"},
+					{name: "title", dataType: "text"},
+					{name: "published", dataType: "boolean"},
+				},
+				constraints: []*liveConstraint{{name: "posts_pkey", kind: "p", columns: []string{"id"}}},
+				indexes:     tt.live,
+			}
+			diff := &SchemaDiff{Tables: []*TableDiff{diffTable(posts, live, rewrites{indexes: tt.definitions})}}
+			got := diff.Statements()
+			if len(got) == 0 {
+				got = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Statements =\n%s\nattendu\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	// La suppression d'un index s'annule en le recréant d'après sa définition en base
+	diff := &SchemaDiff{Tables: []*TableDiff{{Name: "posts", IndexChanges: []IndexChange{
+		{Kind: ChangeRemoved, Name: "posts_legacy_idx", Definition: "CREATE INDEX posts_legacy_idx ON public.posts USING hash (title)"},
+	}}}}
+	if got := diff.RollbackStatements(); !reflect.DeepEqual(got, []string{"CREATE INDEX posts_legacy_idx ON public.posts USING hash (title)"}) {
+		t.Errorf("RollbackStatements = %q", got)
+	}
+}
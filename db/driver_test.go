@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// recordingDriver est un driver database/sql sans base de données : il enregistre chaque
+// requête reçue, préfixée par le numéro de la connexion qui l'exécute (ex: "1: BEGIN").
+// Les requêtes retournent un résultat vide, sauf celles contenant fail qui échouent.
+type recordingDriver struct {
+	mu          sync.Mutex
+	connections int
+	log         []string
+	fail        string
+}
+
+// newRecordingConnection retourne une Connection dont les requêtes sont enregistrées par le driver
+func newRecordingConnection(fail string) (*Connection, *recordingDriver) {
+	recorder := &recordingDriver{fail: fail}
+	return &Connection{db: sql.OpenDB(recorder)}, recorder
+}
+
+// statements retourne les requêtes enregistrées, dans l'ordre d'exécution
+func (d *recordingDriver) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.log...)
+}
+
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connections++
+	return &recordingConn{driver: d, id: d.connections}, nil
+}
+
+func (d *recordingDriver) Driver() driver.Driver {
+	return nil
+}
+
+// record enregistre une requête de la connexion id et retourne l'erreur prévue pour elle
+func (d *recordingDriver) record(id int, statement string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, fmt.Sprintf("%d: %s", id, statement))
+	if d.fail != "" && strings.Contains(statement, d.fail) {
+		return errors.New("échec prévu: " + d.fail)
+	}
+	return nil
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+	id     int
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("Prepare non supporté")
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	if err := c.driver.record(c.id, "BEGIN"); err != nil {
+		return nil, err
+	}
+	return recordingTx{c}, nil
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := c.driver.record(c.id, query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := c.driver.record(c.id, query); err != nil {
+		return nil, err
+	}
+	return emptyRows{}, nil
+}
+
+type recordingTx struct {
+	conn *recordingConn
+}
+
+func (tx recordingTx) Commit() error {
+	return tx.conn.driver.record(tx.conn.id, "COMMIT")
+}
+
+func (tx recordingTx) Rollback() error {
+	return tx.conn.driver.record(tx.conn.id, "ROLLBACK")
+}
+
+// emptyRows est le résultat sans ligne des requêtes enregistrées
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string {
+	return nil
+}
+
+func (emptyRows) Close() error {
+	return nil
+}
+
+func (emptyRows) Next([]driver.Value) error {
+	return io.EOF
+}
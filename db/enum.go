@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Enum représente un type énuméré PostgreSQL (CREATE TYPE ... AS ENUM), utilisable
+// comme type de colonne avec AddEnumAttribute
+type Enum struct {
+	name   string
+	values []string
+}
+
+// NewEnum crée un type énuméré avec ses valeurs, dans l'ordre de tri PostgreSQL
+func NewEnum(name string, values ...string) *Enum {
+	if len(values) == 0 {
+		panic(fmt.Sprintf("le type énuméré %s doit avoir au moins une valeur", name))
+	}
+	return &Enum{
+		name:   name,
+		values: values,
+	}
+}
+
+// BuildSQL retourne la requête de création du type. PostgreSQL ne propose pas
+// CREATE TYPE IF NOT EXISTS : un type déjà existant est ignoré par un bloc DO.
+// CreateEnum complète ensuite les valeurs d'un type existant.
+func (e *Enum) BuildSQL() string {
+	return fmt.Sprintf("DO $$ BEGIN %s; EXCEPTION WHEN duplicate_object THEN NULL; END $$", e.createSQL())
+}
+
+// createSQL retourne l'instruction CREATE TYPE du type
+func (e *Enum) createSQL() string {
+	values := make([]string, len(e.values))
+	for i, value := range e.values {
+		values[i] = quoteLiteral(value)
+	}
+	return fmt.Sprintf("CREATE TYPE \"%s\" AS ENUM (%s)", e.name, strings.Join(values, ", "))
+}
+
+// EnumValueChange représente une valeur à ajouter à un type énuméré existant
+type EnumValueChange struct {
+	Enum  string
+	Value string
+	// Before ou After est la valeur existante à côté de laquelle la valeur est insérée
+	Before string
+	After  string
+}
+
+// addedValues retourne les valeurs déclarées absentes du type existant, dont les valeurs
+// en base sont existing. PostgreSQL ne sait ni supprimer ni réordonner les valeurs d'un
+// type : des valeurs existantes absentes de la déclaration ou déclarées dans un autre
+// ordre sont signalées par une erreur.
+func (e *Enum) addedValues(existing []string) ([]EnumValueChange, error) {
+	incompatible := fmt.Errorf("le type énuméré '%s' existe avec les valeurs (%s), incompatibles avec (%s): les valeurs existantes ne peuvent être ni supprimées ni réordonnées",
+		e.name, strings.Join(existing, ", "), strings.Join(e.values, ", "))
+
+	var changes []EnumValueChange
+	next := 0
+	for i, value := range e.values {
+		if next < len(existing) && existing[next] == value {
+			next++
+			continue
+		}
+		if slices.Contains(existing, value) {
+			return nil, incompatible
+		}
+
+		change := EnumValueChange{Enum: e.name, Value: value}
+		switch {
+		case i > 0:
+			change.After = e.values[i-1]
+		case len(existing) > 0:
+			change.Before = existing[0]
+		}
+		changes = append(changes, change)
+	}
+
+	if next < len(existing) {
+		return nil, incompatible
+	}
+	return changes, nil
+}
+
+// sql retourne l'instruction ALTER TYPE qui ajoute la valeur
+func (c EnumValueChange) sql() string {
+	statement := fmt.Sprintf("ALTER TYPE \"%s\" ADD VALUE IF NOT EXISTS %s", c.Enum, quoteLiteral(c.Value))
+	switch {
+	case c.After != "":
+		statement += " AFTER " + quoteLiteral(c.After)
+	case c.Before != "":
+		statement += " BEFORE " + quoteLiteral(c.Before)
+	}
+	return statement
+}
+
+// CreateEnum crée un type énuméré dans la base de données s'il n'existe pas, ou lui
+// ajoute les valeurs déclarées qui lui manquent
+func (c *Connection) CreateEnum(enum *Enum) error {
+	return c.CreateEnumContext(context.Background(), enum)
+}
+
+// CreateEnumContext crée un type énuméré comme CreateEnum en respectant le contexte
+func (c *Connection) CreateEnumContext(ctx context.Context, enum *Enum) error {
+	liveEnums, err := readEnums(ctx, c, "")
+	if err != nil {
+		return err
+	}
+
+	existing, exists := liveEnums[enum.name]
+	if !exists {
+		if _, err := c.db.ExecContext(ctx, enum.BuildSQL()); err != nil {
+			return fmt.Errorf("failed to create enum type: %w", err)
+		}
+		return nil
+	}
+
+	changes, err := enum.addedValues(existing)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if _, err := c.db.ExecContext(ctx, change.sql()); err != nil {
+			return fmt.Errorf("failed to add enum value %s: %w", change.Value, err)
+		}
+	}
+	return nil
+}
+
+// AddEnumAttribute ajoute à la table une colonne dont le type est le type énuméré donné
+func (tb *TableBuilder) AddEnumAttribute(name string, enum *Enum) *AttributeBuilder {
+	ab := tb.AddAttribute(name, AttributeType(enum.name))
+	ab.attribute.enum = enum
+	return ab
+}
+
+// GetName retourne le nom du type énuméré
+func (e *Enum) GetName() string {
+	return e.name
+}
+
+// GetValues retourne les valeurs du type énuméré
+func (e *Enum) GetValues() []string {
+	return e.values
+}
+
+// GetGoType retourne le nom du type Go généré pour le type énuméré (ex: post_status -> PostStatus)
+func (e *Enum) GetGoType() string {
+	return GoIdentifier(e.name)
+}
+
+// GetEnum retourne le type énuméré de l'attribut, ou nil
+func (a *Attribute) GetEnum() *Enum {
+	return a.enum
+}
+
+// GoIdentifier convertit un nom SQL ou une valeur d'enum en identifiant Go exporté, en
+// supprimant les caractères qui ne sont ni des lettres ni des chiffres et en mettant en
+// majuscule la première lettre de chaque mot (ex: post_status -> PostStatus, "été" -> Été).
+// Le générateur l'utilise pour les types énumérés et les noms de leurs constantes.
+func GoIdentifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var identifier strings.Builder
+	for _, part := range parts {
+		first, size := utf8.DecodeRuneInString(part)
+		identifier.WriteRune(unicode.ToUpper(first))
+		identifier.WriteString(strings.ToLower(part[size:]))
+	}
+	return identifier.String()
+}
+
+// enums retourne les types énumérés utilisés par les tables, dans l'ordre de création.
+// Deux types de même nom avec des valeurs différentes sont signalés par une erreur.
+func (s *Schema) enums(order []string) ([]*Enum, error) {
+	var enums []*Enum
+	seen := make(map[string]*Enum)
+	for _, tableName := range order {
+		for _, attr := range s.tables[tableName].attributes {
+			if attr.enum == nil {
+				continue
+			}
+			if existing, exists := seen[attr.enum.name]; exists {
+				if strings.Join(existing.values, ",") != strings.Join(attr.enum.values, ",") {
+					return nil, fmt.Errorf("le type énuméré '%s' est déclaré avec des valeurs différentes", attr.enum.name)
+				}
+				continue
+			}
+			seen[attr.enum.name] = attr.enum
+			enums = append(enums, attr.enum)
+		}
+	}
+	return enums, nil
+}
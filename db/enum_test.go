@@ -0,0 +1,77 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGoIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"post_status", "PostStatus"},
+		{"in-review", "InReview"},
+		{"ARCHIVED", "Archived"},
+		{"été", "Été"},
+		{"très_élevé", "TrèsÉlevé"},
+		{"2fa", "2fa"},
+		{"a  b", "AB"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GoIdentifier(tt.name); got != tt.want {
+				t.Errorf("GoIdentifier(%q) = %q, attendu %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumAddedValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		existing []string
+		want     []string
+		wantErr  string
+	}{
+		{"valeurs identiques", []string{"draft", "published"}, []string{"draft", "published"}, nil, ""},
+		{
+			name:     "valeurs ajoutées",
+			values:   []string{"new", "draft", "review", "published", "archived"},
+			existing: []string{"draft", "published"},
+			want: []string{
+				`ALTER TYPE "post_status" ADD VALUE IF NOT EXISTS 'new' BEFORE 'draft'`,
+				`ALTER TYPE "post_status" ADD VALUE IF NOT EXISTS 'review' AFTER 'draft'`,
+				`ALTER TYPE "post_status" ADD VALUE IF NOT EXISTS 'archived' AFTER 'published'`,
+			},
+		},
+		{"type sans valeur", []string{"l'été"}, nil, []string{`ALTER TYPE "post_status" ADD VALUE IF NOT EXISTS 'l''été'`}, ""},
+		{"valeur retirée", []string{"draft"}, []string{"draft", "published"}, nil, "existe avec les valeurs (draft, published), incompatibles avec (draft)"},
+		{"valeurs réordonnées", []string{"published", "draft"}, []string{"draft", "published"}, nil, "ne peuvent être ni supprimées ni réordonnées"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := (&Enum{name: "post_status", values: tt.values}).addedValues(tt.existing)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.sql())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("instructions = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"postgo/logging"
+	"strings"
+)
+
+// IndexMethod représente la méthode d'accès d'un index PostgreSQL
+type IndexMethod string
+
+const (
+	BTree IndexMethod = "btree"
+	Hash  IndexMethod = "hash"
+	GIN   IndexMethod = "gin"
+	GiST  IndexMethod = "gist"
+	BRIN  IndexMethod = "brin"
+)
+
+// Index représente un index d'une table, portant sur des colonnes ou sur une expression
+type Index struct {
+	name  string
+	table string
+	// columns contient les colonnes indexées, vide pour un index sur expression
+	columns    []string
+	expression string
+	unique     bool
+	method     IndexMethod
+	// where est le prédicat d'un index partiel, vide sinon
+	where string
+}
+
+// IndexBuilder permet de construire un index avec le pattern builder
+type IndexBuilder struct {
+	index        *Index
+	tableBuilder *TableBuilder // Référence vers le table builder parent
+}
+
+// AddIndex ajoute un index sur une ou plusieurs colonnes de la table, nommé
+// <table>_<colonnes>_idx comme le ferait PostgreSQL (ex: AddIndex("author_id", "published"))
+func (tb *TableBuilder) AddIndex(columns ...string) *IndexBuilder {
+	if len(columns) == 0 {
+		panic(fmt.Sprintf("l'index de la table %s doit porter sur au moins une colonne", tb.name))
+	}
+	return &IndexBuilder{
+		index: &Index{
+			name:    fmt.Sprintf("%s_%s_idx", tb.name, strings.Join(columns, "_")),
+			table:   tb.name,
+			columns: columns,
+		},
+		tableBuilder: tb,
+	}
+}
+
+// AddExpressionIndex ajoute un index sur une expression SQL (ex: "lower(email)").
+// Le nom est obligatoire, PostgreSQL ne pouvant pas le déduire de l'expression.
+func (tb *TableBuilder) AddExpressionIndex(name, expression string) *IndexBuilder {
+	return &IndexBuilder{
+		index: &Index{
+			name:       name,
+			table:      tb.name,
+			expression: expression,
+		},
+		tableBuilder: tb,
+	}
+}
+
+// Name remplace le nom de l'index
+func (ib *IndexBuilder) Name(name string) *IndexBuilder {
+	ib.index.name = name
+	return ib
+}
+
+// Unique rend l'index unique (CREATE UNIQUE INDEX)
+func (ib *IndexBuilder) Unique() *IndexBuilder {
+	ib.index.unique = true
+	return ib
+}
+
+// Using définit la méthode d'accès de l'index (ex: GIN pour un tableau ou du JSONB).
+// Sans appel, PostgreSQL utilise btree.
+func (ib *IndexBuilder) Using(method IndexMethod) *IndexBuilder {
+	ib.index.method = method
+	return ib
+}
+
+// Where restreint l'index aux lignes vérifiant le prédicat (index partiel, ex: Where("published"))
+func (ib *IndexBuilder) Where(predicate string) *IndexBuilder {
+	ib.index.where = predicate
+	return ib
+}
+
+// Build finalise la construction de l'index et l'ajoute à la table
+func (ib *IndexBuilder) Build() *TableBuilder {
+	for _, index := range ib.tableBuilder.indexes {
+		if index.name == ib.index.name {
+			panic(fmt.Sprintf("l'index %s est déclaré plusieurs fois sur la table %s", index.name, ib.tableBuilder.name))
+		}
+	}
+	ib.tableBuilder.indexes = append(ib.tableBuilder.indexes, ib.index)
+	return ib.tableBuilder
+}
+
+// BuildSQL retourne la requête de création de l'index, ignorée si l'index existe déjà
+func (i *Index) BuildSQL() string {
+	return i.buildSQL(false)
+}
+
+// buildSQL retourne la requête de création de l'index. Avec concurrently, l'index est
+// construit sans bloquer les écritures sur la table (CREATE INDEX CONCURRENTLY).
+func (i *Index) buildSQL(concurrently bool) string {
+	var sql strings.Builder
+
+	sql.WriteString("CREATE ")
+	if i.unique {
+		sql.WriteString("UNIQUE ")
+	}
+	sql.WriteString("INDEX ")
+	if concurrently {
+		sql.WriteString("CONCURRENTLY ")
+	}
+	fmt.Fprintf(&sql, "IF NOT EXISTS \"%s\" ON \"%s\"", i.name, i.table)
+	if i.method != "" {
+		fmt.Fprintf(&sql, " USING %s", i.method)
+	}
+	fmt.Fprintf(&sql, " (%s)", i.definition())
+	if i.where != "" {
+		fmt.Fprintf(&sql, " WHERE %s", i.where)
+	}
+
+	return sql.String()
+}
+
+// definition retourne la liste des éléments indexés : les colonnes ou l'expression
+func (i *Index) definition() string {
+	if i.expression != "" {
+		return fmt.Sprintf("(%s)", i.expression)
+	}
+	quoted := make([]string, len(i.columns))
+	for j, column := range i.columns {
+		quoted[j] = fmt.Sprintf("\"%s\"", column)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// CreateIndex crée un index dans la base de données s'il n'existe pas
+func (c *Connection) CreateIndex(index *Index) error {
+	return c.CreateIndexContext(context.Background(), index)
+}
+
+// CreateIndexContext crée un index comme CreateIndex en respectant le contexte
+func (c *Connection) CreateIndexContext(ctx context.Context, index *Index) error {
+	return c.createIndex(ctx, index, false)
+}
+
+// CreateIndexConcurrently crée un index sans bloquer les écritures sur une table existante.
+// PostgreSQL interdit CREATE INDEX CONCURRENTLY dans une transaction.
+func (c *Connection) CreateIndexConcurrently(index *Index) error {
+	return c.CreateIndexConcurrentlyContext(context.Background(), index)
+}
+
+// CreateIndexConcurrentlyContext crée un index comme CreateIndexConcurrently en respectant le contexte
+func (c *Connection) CreateIndexConcurrentlyContext(ctx context.Context, index *Index) error {
+	return c.createIndex(ctx, index, true)
+}
+
+// createIndex exécute la création de l'index. Une construction CONCURRENTLY interrompue
+// laisse un index INVALID que IF NOT EXISTS ignorerait : il est supprimé puis reconstruit,
+// et l'index créé CONCURRENTLY est vérifié.
+func (c *Connection) createIndex(ctx context.Context, index *Index, concurrently bool) error {
+	exists, valid, err := c.indexValidity(ctx, index)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	if exists && !valid {
+		logging.Warning.Printf("Index '%s' invalide (construction interrompue): suppression puis reconstruction", index.name)
+		drop := "DROP INDEX "
+		if concurrently {
+			drop += "CONCURRENTLY "
+		}
+		if _, err := c.db.ExecContext(ctx, drop+fmt.Sprintf("IF EXISTS \"%s\"", index.name)); err != nil {
+			return fmt.Errorf("failed to drop invalid index: %w", err)
+		}
+	}
+
+	if _, err := c.db.ExecContext(ctx, index.buildSQL(concurrently)); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	if !concurrently {
+		return nil
+	}
+
+	if exists, valid, err = c.indexValidity(ctx, index); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	if exists && !valid {
+		return fmt.Errorf("failed to create index: l'index %s est INVALID après sa construction", index.name)
+	}
+	return nil
+}
+
+// indexValidity indique si l'index existe dans le chemin de recherche et s'il est
+// utilisable (pg_index.indisvalid)
+func (c *Connection) indexValidity(ctx context.Context, index *Index) (exists, valid bool, err error) {
+	err = c.db.QueryRowContext(ctx,
+		"SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass(quote_ident($1))", index.name).Scan(&valid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	return true, valid, nil
+}
+
+// GetName retourne le nom de l'index
+func (i *Index) GetName() string {
+	return i.name
+}
+
+// GetTable retourne le nom de la table indexée
+func (i *Index) GetTable() string {
+	return i.table
+}
+
+// GetColumns retourne les colonnes indexées, vide pour un index sur expression
+func (i *Index) GetColumns() []string {
+	return i.columns
+}
+
+// GetExpression retourne l'expression indexée, vide pour un index sur colonnes
+func (i *Index) GetExpression() string {
+	return i.expression
+}
+
+// IsUnique vérifie si l'index est unique
+func (i *Index) IsUnique() bool {
+	return i.unique
+}
+
+// GetMethod retourne la méthode d'accès de l'index, vide pour la méthode par défaut (btree)
+func (i *Index) GetMethod() IndexMethod {
+	return i.method
+}
+
+// GetWhere retourne le prédicat d'un index partiel, vide sinon
+func (i *Index) GetWhere() string {
+	return i.where
+}
+
+// GetIndexes retourne les index déclarés sur la table
+func (tb *TableBuilder) GetIndexes() []*Index {
+	return tb.indexes
+}
@@ -0,0 +1,41 @@
+package db
+
+import "testing"
+
+func TestIndexBuildSQL(t *testing.T) {
+	tests := []struct {
+		name         string
+		table        *TableBuilder
+		concurrently bool
+		want         string
+	}{
+		{
+			name:  "colonnes",
+			table: NewTable("posts").AddIndex("author_id", "published").Build(),
+			want:  `CREATE INDEX IF NOT EXISTS "posts_author_id_published_idx" ON "posts" ("author_id", "published")`,
+		},
+		{
+			name:  "unique sur expression",
+			table: NewTable("users").AddExpressionIndex("users_email_lower_idx", "lower(email)").Unique().Build(),
+			want:  `CREATE UNIQUE INDEX IF NOT EXISTS "users_email_lower_idx" ON "users" ((lower(email)))`,
+		},
+		{
+			name:  "méthode et prédicat",
+			table: NewTable("posts").AddIndex("tags").Using(GIN).Where("published").Name("posts_published_tags_idx").Build(),
+			want:  `CREATE INDEX IF NOT EXISTS "posts_published_tags_idx" ON "posts" USING gin ("tags") WHERE published`,
+		},
+		{
+			name:         "construction concurrente",
+			table:        NewTable("posts").AddIndex("published_at").Build(),
+			concurrently: true,
+			want:         `CREATE INDEX CONCURRENTLY IF NOT EXISTS "posts_published_at_idx" ON "posts" ("published_at")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.table.GetIndexes()[0].buildSQL(tt.concurrently); got != tt.want {
+				t.Errorf("buildSQL = %s, attendu %s", got, tt.want)
+			}
+		})
+	}
+}
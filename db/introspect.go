@@ -0,0 +1,488 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"go/format"
+	"go/token"
+	"postgo/logging"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// attributeTypes liste les constantes AttributeType du package et leur nom dans le code source
+var attributeTypes = []struct {
+	attributeType AttributeType
+	name          string
+}{
+	{String, "String"},
+	{Text, "Text"},
+	{SmallInt, "SmallInt"},
+	{Integer, "Integer"},
+	{BigInt, "BigInt"},
+	{Float, "Float"},
+	{Numeric, "Numeric"},
+	{Boolean, "Boolean"},
+	{Date, "Date"},
+	{Timestamp, "Timestamp"},
+	{TimestampTZ, "TimestampTZ"},
+	{UUID, "UUID"},
+	{JSON, "JSON"},
+	{JSONB, "JSONB"},
+	{Bytea, "Bytea"},
+}
+
+// parameterizedTypeFunctions associe les types paramétrés à la fonction qui les construit
+// et à son nombre de paramètres
+var parameterizedTypeFunctions = map[string]struct {
+	name   string
+	params int
+}{
+	"character varying": {"Varchar", 1},
+	"character":         {"Char", 1},
+	"numeric":           {"NumericOf", 2},
+}
+
+// keyStrategyNames associe les stratégies de clé primaire à leurs constantes
+var keyStrategyNames = map[KeyStrategy]string{
+	SerialKey:    "SerialKey",
+	BigSerialKey: "BigSerialKey",
+	IdentityKey:  "IdentityKey",
+	UUIDKey:      "UUIDKey",
+}
+
+// identityGenerationNames associe les modes des colonnes IDENTITY à leurs constantes
+var identityGenerationNames = map[IdentityGeneration]string{
+	GeneratedAlways:    "GeneratedAlways",
+	GeneratedByDefault: "GeneratedByDefault",
+}
+
+// indexMethodNames associe les méthodes d'accès des index à leurs constantes
+var indexMethodNames = map[IndexMethod]string{
+	BTree: "BTree",
+	Hash:  "Hash",
+	GIN:   "GIN",
+	GiST:  "GiST",
+	BRIN:  "BRIN",
+}
+
+// referentialActionNames associe les actions référentielles à leurs constantes
+var referentialActionNames = map[ReferentialAction]string{
+	Cascade:  "Cascade",
+	SetNull:  "SetNull",
+	Restrict: "Restrict",
+}
+
+// IntrospectTables lit les tables d'un schéma PostgreSQL existant et les convertit
+// en TableBuilder. Un nom de schéma vide désigne le schéma courant.
+// Les éléments que le DSL ne sait pas représenter sont signalés par un avertissement.
+func IntrospectTables(conn *Connection, schemaName string) ([]*TableBuilder, error) {
+	return IntrospectTablesContext(context.Background(), conn, schemaName)
+}
+
+// IntrospectTablesContext lit les tables d'un schéma comme IntrospectTables en respectant le contexte
+func IntrospectTablesContext(ctx context.Context, conn *Connection, schemaName string) ([]*TableBuilder, error) {
+	liveTables, err := readCatalog(ctx, conn, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*TableBuilder, 0, len(liveTables))
+	enums := make(map[string]*Enum)
+	for _, live := range liveTables {
+		if live.name == migrationsTable {
+			continue
+		}
+		tables = append(tables, introspectTable(live, enums))
+	}
+
+	return tables, nil
+}
+
+// introspectTable convertit une table lue depuis le catalogue en TableBuilder. Les types
+// énumérés déjà rencontrés sont partagés entre les tables par enums.
+func introspectTable(live *liveTable, enums map[string]*Enum) *TableBuilder {
+	tb := NewTable(live.name)
+	// used contient les contraintes reprises par le DSL, les autres sont signalées
+	used := make(map[*liveConstraint]bool)
+
+	primaryKey := live.primaryKey()
+	strategy := liveKeyStrategy(live)
+	if strategy != "" {
+		tb.WithKey(strategy)
+		used[primaryKey] = true
+	} else {
+		tb.WithoutKey()
+	}
+
+	for _, column := range live.columns {
+		if column.name == "id" && strategy != "" {
+			continue
+		}
+
+		var ab *AttributeBuilder
+		if enum := introspectEnum(column, enums); enum != nil {
+			ab = tb.AddEnumAttribute(column.name, enum)
+		} else {
+			ab = tb.AddAttribute(column.name, attributeTypeFromCatalog(column.dataType))
+		}
+		if column.notNull {
+			ab.NotNull()
+		}
+		if unique := live.columnConstraint("u", column.name); unique != nil {
+			ab.Unique()
+			used[unique] = true
+		}
+		if fk := live.columnConstraint("f", column.name); fk != nil && len(fk.referencedColumns) == 1 {
+			ab.References(fk.referencedTable, fk.referencedColumns[0])
+			if fk.onDelete != "" {
+				ab.OnDelete(fk.onDelete)
+			}
+			if fk.onUpdate != "" {
+				ab.OnUpdate(fk.onUpdate)
+			}
+			used[fk] = true
+		}
+		if column.identity != "" {
+			ab.Identity(column.identity)
+		}
+		if column.generated {
+			ab.GeneratedAlwaysAs(column.defaultExpr)
+		} else if column.defaultExpr != "" {
+			ab.DefaultExpr(column.defaultExpr)
+		}
+		for _, check := range live.constraints {
+			if check.kind == "c" && len(check.columns) == 1 && check.columns[0] == column.name {
+				ab.CheckNamed(check.name, checkExpression(check.definition))
+				used[check] = true
+			}
+		}
+		ab.Build()
+	}
+
+	for _, constraint := range live.constraints {
+		switch {
+		case used[constraint]:
+		case constraint.kind == "c":
+			tb.Check(constraint.name, checkExpression(constraint.definition))
+		case constraint.kind == "u" && len(constraint.columns) > 1:
+			tb.Unique(constraint.columns...)
+		case constraint.kind == "p" && strategy == "":
+			tb.PrimaryKey(constraint.columns...)
+		default:
+			logging.Warning.Printf("Contrainte '%s' de la table '%s' ignorée: non supportée par le DSL", constraint.name, live.name)
+		}
+	}
+
+	for _, index := range live.indexes {
+		introspectIndex(tb, index)
+	}
+
+	return tb
+}
+
+// introspectEnum retourne le type énuméré d'une colonne, partagé par les colonnes du
+// même type, ou nil si la colonne n'est pas d'un type énuméré
+func introspectEnum(column *liveColumn, enums map[string]*Enum) *Enum {
+	if column.enumName == "" {
+		return nil
+	}
+	if enum, exists := enums[column.enumName]; exists {
+		return enum
+	}
+	if len(column.enumValues) == 0 {
+		logging.Warning.Printf("Type énuméré '%s' de la colonne '%s' ignoré: il n'a aucune valeur", column.enumName, column.name)
+		return nil
+	}
+	enum := NewEnum(column.enumName, column.enumValues...)
+	enums[column.enumName] = enum
+	return enum
+}
+
+// introspectIndex ajoute à la table un index lu depuis le catalogue, ou le signale par
+// un avertissement lorsque le DSL ne sait pas le représenter
+func introspectIndex(tb *TableBuilder, live *liveIndex) {
+	ignore := func(reason string) {
+		logging.Warning.Printf("Index '%s' de la table '%s' ignoré: %s", live.name, tb.name, reason)
+	}
+
+	expressions := 0
+	for _, column := range live.columns {
+		if column == "" {
+			expressions++
+		}
+	}
+
+	var ib *IndexBuilder
+	switch {
+	case !live.valid:
+		ignore("index invalide, sa construction a échoué (à supprimer puis recréer)")
+		return
+	case live.included:
+		ignore("colonnes INCLUDE non supportées par le DSL")
+		return
+	case live.ordered:
+		ignore("tri DESC ou NULLS FIRST/LAST non supporté par le DSL")
+		return
+	case expressions == 0:
+		ib = tb.AddIndex(live.columns...).Name(live.name)
+	case len(live.columns) == 1:
+		ib = tb.AddExpressionIndex(live.name, live.expression)
+	default:
+		ignore("index mêlant colonnes et expressions non supporté par le DSL")
+		return
+	}
+
+	if live.unique {
+		ib.Unique()
+	}
+	if live.method != string(BTree) {
+		ib.Using(IndexMethod(live.method))
+	}
+	if live.predicate != "" {
+		ib.Where(live.predicate)
+	}
+	ib.Build()
+}
+
+// checkExpression extrait l'expression d'une définition CHECK retournée par
+// pg_get_constraintdef (ex: "CHECK ((employee_count >= 0))" -> "(employee_count >= 0)")
+func checkExpression(definition string) string {
+	expression := strings.TrimPrefix(definition, "CHECK (")
+	expression = strings.TrimSuffix(expression, " NOT VALID")
+	expression = strings.TrimSuffix(expression, " NO INHERIT")
+	return strings.TrimSuffix(expression, ")")
+}
+
+// liveKeyStrategy retourne la stratégie de la clé primaire id de la table, telle que
+// WithKey la déclarerait, ou une chaîne vide si la clé primaire n'est pas une colonne
+// id attribuée par PostgreSQL. Une autre colonne IDENTITY (INTEGER, BY DEFAULT ou d'un
+// autre nom) est reprise avec Identity et la clé primaire avec PrimaryKey.
+func liveKeyStrategy(live *liveTable) KeyStrategy {
+	column := live.column("id")
+	if column == nil || live.columnConstraint("p", "id") == nil {
+		return ""
+	}
+
+	dataType := canonicalType(column.dataType)
+	switch {
+	case column.identity == GeneratedAlways && dataType == canonicalType(string(BigInt)):
+		return IdentityKey
+	case strings.HasPrefix(column.defaultExpr, "nextval(") && dataType == canonicalType("SERIAL"):
+		return SerialKey
+	case strings.HasPrefix(column.defaultExpr, "nextval(") && dataType == canonicalType("BIGSERIAL"):
+		return BigSerialKey
+	case dataType == canonicalType(string(UUID)) && column.defaultExpr == "gen_random_uuid()":
+		return UUIDKey
+	}
+	return ""
+}
+
+// attributeTypeFromCatalog convertit un type retourné par format_type en AttributeType
+func attributeTypeFromCatalog(dataType string) AttributeType {
+	for _, known := range attributeTypes {
+		if canonicalType(string(known.attributeType)) == canonicalType(dataType) {
+			return known.attributeType
+		}
+	}
+	return AttributeType(dataType)
+}
+
+// GenerateSchemaSource produit le code source Go des définitions de tables dans le package
+// packageName : une fonction IntrospectedSchema qui enregistre les tables, déclarées avec
+// db.NewTable(...).AddAttribute(...), dans un nouveau schéma.
+func GenerateSchemaSource(tables []*TableBuilder, packageName string) ([]byte, error) {
+	if !token.IsIdentifier(packageName) {
+		return nil, fmt.Errorf("nom de package invalide: %q", packageName)
+	}
+
+	var source strings.Builder
+
+	source.WriteString("// Code généré automatiquement par introspection de la base de données\n")
+	fmt.Fprintf(&source, "package %s\n\n", packageName)
+	source.WriteString("import \"postgo/db\"\n")
+
+	enums := make(map[*Enum]string)
+	for _, table := range tables {
+		for _, attr := range table.attributes {
+			if attr.enum == nil || enums[attr.enum] != "" {
+				continue
+			}
+			enums[attr.enum] = enumVariableName(attr.enum)
+			values := make([]string, len(attr.enum.values))
+			for i, value := range attr.enum.values {
+				values[i] = fmt.Sprintf("%q", value)
+			}
+			fmt.Fprintf(&source, "\nvar %s = db.NewEnum(%q, %s)\n", enums[attr.enum], attr.enum.name, strings.Join(values, ", "))
+		}
+	}
+
+	source.WriteString("\n// IntrospectedSchema retourne le schéma des tables lues depuis la base de données\n")
+	source.WriteString("func IntrospectedSchema() *db.Schema {\n")
+	source.WriteString("\tschema := db.NewSchema()\n")
+	source.WriteString("\tschema.MustRegister(\n")
+	for _, table := range tables {
+		fmt.Fprintf(&source, "\t\t// Table %s\n", table.name)
+		writeTableSource(&source, table, enums)
+	}
+	source.WriteString("\t)\n")
+	source.WriteString("\treturn schema\n")
+	source.WriteString("}\n")
+
+	return format.Source([]byte(source.String()))
+}
+
+// writeTableSource écrit l'expression qui construit la définition d'une table, argument
+// de MustRegister. enums associe les types énumérés des colonnes à la variable qui les déclare.
+func writeTableSource(source *strings.Builder, table *TableBuilder, enums map[*Enum]string) {
+	fmt.Fprintf(source, "\t\tdb.NewTable(%q)", table.name)
+	switch {
+	case table.key == "":
+		source.WriteString(".\n\t\t\tWithoutKey()")
+	case table.key != SerialKey:
+		fmt.Fprintf(source, ".\n\t\t\tWithKey(db.%s)", keyStrategyNames[table.key])
+	}
+
+	for _, attr := range table.attributes {
+		if attr.name == "id" && table.key != "" {
+			continue
+		}
+
+		if attr.enum != nil {
+			fmt.Fprintf(source, ".\n\t\t\tAddEnumAttribute(%q, %s)", attr.name, enums[attr.enum])
+		} else {
+			fmt.Fprintf(source, ".\n\t\t\tAddAttribute(%q, %s)", attr.name, attributeTypeSource(attr.dataType))
+		}
+		if attr.IsRequired() {
+			source.WriteString(".NotNull()")
+		}
+		if attr.identity != "" {
+			fmt.Fprintf(source, ".Identity(db.%s)", identityGenerationNames[attr.identity])
+		}
+		if attr.defaultExpr != "" {
+			fmt.Fprintf(source, ".DefaultExpr(%q)", attr.defaultExpr)
+		}
+		if attr.generatedExpr != "" {
+			fmt.Fprintf(source, ".GeneratedAlwaysAs(%q)", attr.generatedExpr)
+		}
+		if attr.IsUnique() {
+			source.WriteString(".Unique()")
+		}
+		// Check nomme les contraintes comme PostgreSQL, les autres noms sont conservés
+		named := &Attribute{name: attr.name}
+		for _, check := range attr.checks {
+			if check.name == named.defaultCheckName(table.name) {
+				fmt.Fprintf(source, ".Check(%q)", check.expression)
+			} else {
+				fmt.Fprintf(source, ".CheckNamed(%q, %q)", check.name, check.expression)
+			}
+			named.checks = append(named.checks, check)
+		}
+		if attr.reference != nil {
+			fmt.Fprintf(source, ".References(%q, %q)", attr.reference.table, attr.reference.column)
+			if attr.reference.onDelete != "" {
+				fmt.Fprintf(source, ".OnDelete(%s)", referentialActionSource(attr.reference.onDelete))
+			}
+			if attr.reference.onUpdate != "" {
+				fmt.Fprintf(source, ".OnUpdate(%s)", referentialActionSource(attr.reference.onUpdate))
+			}
+		}
+		source.WriteString(".Build()")
+	}
+
+	for _, constraint := range table.constraints {
+		switch constraint.kind {
+		case CheckConstraint:
+			fmt.Fprintf(source, ".\n\t\t\tCheck(%q, %q)", constraint.name, constraint.expression)
+		case UniqueConstraint:
+			fmt.Fprintf(source, ".\n\t\t\tUnique(%s)", quotedList(constraint.columns))
+		case PrimaryKeyConstraint:
+			fmt.Fprintf(source, ".\n\t\t\tPrimaryKey(%s)", quotedList(constraint.columns))
+		}
+	}
+
+	for _, index := range table.indexes {
+		if index.expression != "" {
+			fmt.Fprintf(source, ".\n\t\t\tAddExpressionIndex(%q, %q)", index.name, index.expression)
+		} else {
+			fmt.Fprintf(source, ".\n\t\t\tAddIndex(%s)", quotedList(index.columns))
+			if index.name != fmt.Sprintf("%s_%s_idx", table.name, strings.Join(index.columns, "_")) {
+				fmt.Fprintf(source, ".Name(%q)", index.name)
+			}
+		}
+		if index.unique {
+			source.WriteString(".Unique()")
+		}
+		if index.method != "" {
+			fmt.Fprintf(source, ".Using(%s)", indexMethodSource(index.method))
+		}
+		if index.where != "" {
+			fmt.Fprintf(source, ".Where(%q)", index.where)
+		}
+		source.WriteString(".Build()")
+	}
+
+	source.WriteString(",\n")
+}
+
+// attributeTypeSource retourne l'expression Go désignant un AttributeType : une constante,
+// un appel à Varchar, Char, NumericOf ou ArrayOf, ou une conversion explicite
+func attributeTypeSource(dataType AttributeType) string {
+	canonical := canonicalType(string(dataType))
+	if strings.HasSuffix(canonical, "[]") {
+		return fmt.Sprintf("db.ArrayOf(%s)", attributeTypeSource(AttributeType(strings.TrimSuffix(canonical, "[]"))))
+	}
+
+	for _, known := range attributeTypes {
+		if canonicalType(string(known.attributeType)) == canonical {
+			return "db." + known.name
+		}
+	}
+
+	if open := strings.Index(canonical, "("); open >= 0 && strings.HasSuffix(canonical, ")") {
+		params := strings.Split(canonical[open+1:len(canonical)-1], ",")
+		if function, known := parameterizedTypeFunctions[canonical[:open]]; known && len(params) == function.params {
+			return fmt.Sprintf("db.%s(%s)", function.name, strings.Join(params, ", "))
+		}
+	}
+	return fmt.Sprintf("db.AttributeType(%q)", string(dataType))
+}
+
+// indexMethodSource retourne l'expression Go désignant une IndexMethod
+func indexMethodSource(method IndexMethod) string {
+	if name, known := indexMethodNames[method]; known {
+		return "db." + name
+	}
+	return fmt.Sprintf("db.IndexMethod(%q)", string(method))
+}
+
+// enumVariableName retourne le nom de la variable déclarant un type énuméré, dans le
+// style de db/schema.go (ex: "post_status" -> postStatus). Le nom ne masque ni le
+// package db ni la variable schema de IntrospectedSchema.
+func enumVariableName(enum *Enum) string {
+	identifier := enum.GetGoType()
+	first, size := utf8.DecodeRuneInString(identifier)
+	name := string(unicode.ToLower(first)) + identifier[size:]
+	if !token.IsIdentifier(name) || name == "db" || name == "schema" {
+		name = "enum" + identifier
+	}
+	return name
+}
+
+// quotedList retourne la liste de chaînes Go correspondant aux noms donnés (ex: "a", "b")
+func quotedList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// referentialActionSource retourne l'expression Go désignant une ReferentialAction
+func referentialActionSource(action ReferentialAction) string {
+	if name, known := referentialActionNames[action]; known {
+		return "db." + name
+	}
+	return fmt.Sprintf("db.ReferentialAction(%q)", string(action))
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"postgo/logging"
+)
+
+// legacyPosts retourne une table posts telle que lue depuis le catalogue
+func legacyPosts() *liveTable {
+	return &liveTable{
+		name: "posts",
+		columns: []*liveColumn{
+			{name: "id", dataType: "integer", notNull: true, defaultExpr: "nextval('posts_id_seq'::regclass)"},
+			{name: "status", dataType: "post_status", notNull: true, enumName: "post_status", enumValues: []string{"draft", "published"}},
+			{name: "price", dataType: "numeric(10,2)"},
+			{name: "author_id", dataType: "integer"},
+			{name: "editor_id", dataType: "integer"},
+			{name: "revision", dataType: "bigint", notNull: true, identity: GeneratedByDefault},
+		},
+		constraints: []*liveConstraint{
+			{name: "posts_pkey", kind: "p", columns: []string{"id"}},
+			{name: "positive_price", kind: "c", columns: []string{"price"}, definition: "CHECK ((price > (0)::numeric))"},
+			{name: "posts_price_check", kind: "c", columns: []string{"price"}, definition: "CHECK ((price < (1000)::numeric))"},
+			{name: "posts_editor_id_check", kind: "c", columns: []string{"editor_id"}, definition: "CHECK ((editor_id > 0)) NO INHERIT"},
+			{name: "posts_people_check", kind: "c", columns: []string{"author_id", "editor_id"}, definition: "CHECK ((author_id <> editor_id))"},
+			{name: "posts_people_fkey", kind: "f", columns: []string{"author_id", "editor_id"}},
+			{name: "posts_no_overlap", kind: "x", columns: []string{"author_id"}},
+		},
+		indexes: []*liveIndex{
+			{name: "posts_author_id_idx", method: "btree", columns: []string{"author_id"}, valid: true},
+			{name: "posts_status_open", method: "hash", columns: []string{"status"}, predicate: "(status = 'draft'::post_status)", valid: true},
+			{name: "posts_lower_status", unique: true, method: "btree", columns: []string{""}, expression: "lower((status)::text)", valid: true},
+			{name: "posts_broken_idx", method: "btree", columns: []string{"editor_id"}},
+			{name: "posts_covering_idx", method: "btree", columns: []string{"author_id"}, included: true, valid: true},
+			{name: "posts_desc_idx", method: "btree", columns: []string{"editor_id"}, ordered: true, valid: true},
+		},
+	}
+}
+
+func TestIntrospectTable(t *testing.T) {
+	var warnings bytes.Buffer
+	previous := logging.Warning.Writer()
+	logging.Warning.SetOutput(&warnings)
+	defer logging.Warning.SetOutput(previous)
+
+	// Clé primaire IDENTITY INTEGER : elle n'est pas une clé IdentityKey
+	tickets := &liveTable{
+		name: "tickets",
+		columns: []*liveColumn{
+			{name: "id", dataType: "integer", notNull: true, identity: GeneratedAlways},
+			{name: "subject", dataType: "text"},
+		},
+		constraints: []*liveConstraint{{name: "tickets_pkey", kind: "p", columns: []string{"id"}}},
+	}
+
+	enums := make(map[string]*Enum)
+	posts := introspectTable(legacyPosts(), enums)
+	source, err := GenerateSchemaSource([]*TableBuilder{posts, introspectTable(tickets, enums)}, "legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"package de l'appelant", "package legacy\n\nimport \"postgo/db\"\n"},
+		{"enregistrement dans un nouveau schéma", "schema := db.NewSchema()\n\tschema.MustRegister(\n\t\t// Table posts\n\t\tdb.NewTable(\"posts\")."},
+		{"type énuméré déclaré", `var postStatus = db.NewEnum("post_status", "draft", "published")`},
+		{"colonne de type énuméré", `AddEnumAttribute("status", postStatus).NotNull().Build()`},
+		{"CHECK de colonne nommé", `CheckNamed("positive_price", "(price > (0)::numeric)")`},
+		{"CHECK de colonne au nom par défaut", `AddAttribute("editor_id", db.Integer).Check("(editor_id > 0)")`},
+		{"colonne IDENTITY BY DEFAULT", `AddAttribute("revision", db.BigInt).NotNull().Identity(db.GeneratedByDefault).Build()`},
+		{"clé primaire IDENTITY INTEGER", "WithoutKey().\n\t\t\tAddAttribute(\"id\", db.Integer).NotNull().Identity(db.GeneratedAlways).Build()."},
+		{"clé primaire reprise", `PrimaryKey("id"),`},
+		{"second CHECK de colonne", `CheckNamed("posts_price_check", "(price < (1000)::numeric)")`},
+		{"CHECK multi-colonnes", `Check("posts_people_check", "(author_id <> editor_id)")`},
+		{"index au nom par défaut", `AddIndex("author_id").Build()`},
+		{"index partiel nommé", `AddIndex("status").Name("posts_status_open").Using(db.Hash).Where("(status = 'draft'::post_status)").Build()`},
+		{"index sur expression", `AddExpressionIndex("posts_lower_status", "lower((status)::text)").Unique().Build()`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(string(source), tt.want) {
+				t.Errorf("source générée sans %s:\n%s", tt.want, source)
+			}
+		})
+	}
+
+	for _, dropped := range []string{"posts_people_fkey", "posts_no_overlap", "posts_broken_idx", "posts_covering_idx", "posts_desc_idx"} {
+		if !strings.Contains(warnings.String(), dropped) {
+			t.Errorf("aucun avertissement pour %s:\n%s", dropped, warnings.String())
+		}
+	}
+	if strings.Contains(warnings.String(), "posts_pkey") || strings.Contains(warnings.String(), "positive_price") {
+		t.Errorf("avertissement inattendu:\n%s", warnings.String())
+	}
+
+	// Le type énuméré est partagé avec les autres tables qui l'utilisent
+	if enums["post_status"] == nil || posts.attributes[1].enum != enums["post_status"] {
+		t.Errorf("le type énuméré post_status n'est pas partagé")
+	}
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+)
+
+// KeyStrategy représente la façon dont PostgreSQL attribue la clé primaire id
+// ajoutée par NewTable
+type KeyStrategy string
+
+const (
+	// SerialKey est la stratégie par défaut : id SERIAL PRIMARY KEY (entier 32 bits)
+	SerialKey KeyStrategy = "SERIAL"
+	// BigSerialKey déclare id BIGSERIAL PRIMARY KEY (entier 64 bits)
+	BigSerialKey KeyStrategy = "BIGSERIAL"
+	// IdentityKey déclare id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY (norme SQL)
+	IdentityKey KeyStrategy = "IDENTITY"
+	// UUIDKey déclare id UUID DEFAULT gen_random_uuid() PRIMARY KEY
+	UUIDKey KeyStrategy = "UUID"
+)
+
+// IdentityGeneration indique comment PostgreSQL attribue une colonne IDENTITY
+type IdentityGeneration string
+
+const (
+	// GeneratedAlways refuse les valeurs fournies par INSERT (GENERATED ALWAYS AS IDENTITY)
+	GeneratedAlways IdentityGeneration = "ALWAYS"
+	// GeneratedByDefault accepte les valeurs fournies par INSERT (GENERATED BY DEFAULT AS IDENTITY)
+	GeneratedByDefault IdentityGeneration = "BY DEFAULT"
+)
+
+// Identity déclare une colonne entière attribuée par PostgreSQL (GENERATED ... AS IDENTITY).
+// WithKey(IdentityKey) reste la façon usuelle de déclarer la clé id.
+func (ab *AttributeBuilder) Identity(generation IdentityGeneration) *AttributeBuilder {
+	ab.attribute.identity = generation
+	return ab
+}
+
+// newKeyAttribute crée la colonne id PRIMARY KEY correspondant à la stratégie
+func newKeyAttribute(strategy KeyStrategy) *Attribute {
+	id := &Attribute{
+		name:        "id",
+		constraints: []string{"PRIMARY KEY"},
+	}
+
+	switch strategy {
+	case SerialKey, BigSerialKey:
+		id.dataType = AttributeType(strategy)
+	case IdentityKey:
+		id.dataType = BigInt
+		id.identity = GeneratedAlways
+	case UUIDKey:
+		id.dataType = UUID
+		id.defaultExpr = "gen_random_uuid()"
+	default:
+		panic(fmt.Sprintf("stratégie de clé primaire inconnue: %s", strategy))
+	}
+
+	return id
+}
+
+// WithKey remplace la clé primaire id SERIAL ajoutée par NewTable par une clé id
+// de la stratégie donnée (ex: NewTable("events").WithKey(UUIDKey))
+func (tb *TableBuilder) WithKey(strategy KeyStrategy) *TableBuilder {
+	if slices.ContainsFunc(tb.constraints, func(constraint *Constraint) bool { return constraint.kind == PrimaryKeyConstraint }) {
+		panic(fmt.Sprintf("la table %s déclare déjà une clé primaire avec PrimaryKey", tb.name))
+	}
+
+	id := newKeyAttribute(strategy)
+	if index := tb.keyIndex(); index >= 0 {
+		tb.attributes[index] = id
+	} else {
+		tb.attributes = slices.Insert(tb.attributes, 0, id)
+	}
+	tb.key = strategy
+	return tb
+}
+
+// WithoutKey retire la clé primaire id ajoutée par NewTable : la table n'a pas de clé
+// primaire, sauf si une clé naturelle ou composite est déclarée avec PrimaryKey
+func (tb *TableBuilder) WithoutKey() *TableBuilder {
+	if index := tb.keyIndex(); index >= 0 {
+		tb.attributes = slices.Delete(tb.attributes, index, index+1)
+	}
+	tb.key = ""
+	return tb
+}
+
+// keyIndex retourne la position de la colonne id ajoutée par NewTable ou WithKey, -1 si absente
+func (tb *TableBuilder) keyIndex() int {
+	if tb.key == "" {
+		return -1
+	}
+	return slices.IndexFunc(tb.attributes, func(attr *Attribute) bool {
+		return attr.name == "id" && attr.IsPrimaryKey()
+	})
+}
+
+// GetKeyStrategy retourne la stratégie de la clé primaire id, vide si la table n'a pas
+// de colonne id ajoutée par NewTable ou WithKey
+func (tb *TableBuilder) GetKeyStrategy() KeyStrategy {
+	return tb.key
+}
+
+// GetPrimaryKey retourne les colonnes de la clé primaire, dans l'ordre de la clé.
+// Le résultat est vide pour une table sans clé primaire.
+func (tb *TableBuilder) GetPrimaryKey() []*Attribute {
+	var key []*Attribute
+	for _, constraint := range tb.constraints {
+		if constraint.kind != PrimaryKeyConstraint {
+			continue
+		}
+		for _, column := range constraint.columns {
+			if index := slices.IndexFunc(tb.attributes, func(attr *Attribute) bool { return attr.name == column }); index >= 0 {
+				key = append(key, tb.attributes[index])
+			}
+		}
+		return key
+	}
+
+	for _, attr := range tb.attributes {
+		if attr.IsPrimaryKey() {
+			key = append(key, attr)
+		}
+	}
+	return key
+}
+
+// IsIdentity vérifie si la colonne est une colonne GENERATED ... AS IDENTITY
+func (a *Attribute) IsIdentity() bool {
+	return a.identity != ""
+}
+
+// IsAutoIncrement vérifie si PostgreSQL attribue lui-même la valeur de la colonne à partir
+// d'une séquence (SMALLSERIAL, SERIAL, BIGSERIAL ou IDENTITY). Le code généré ne l'écrit jamais.
+func (a *Attribute) IsAutoIncrement() bool {
+	switch a.dataType {
+	case "SMALLSERIAL", "SERIAL", "BIGSERIAL":
+		return true
+	}
+	return a.identity != ""
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		table         *TableBuilder
+		wantSQL       string
+		wantKey       []string
+		autoIncrement bool
+	}{
+		{
+			name:          "SERIAL par défaut",
+			table:         NewTable("users"),
+			wantSQL:       `CREATE TABLE IF NOT EXISTS "users" ("id" SERIAL PRIMARY KEY)`,
+			wantKey:       []string{"id"},
+			autoIncrement: true,
+		},
+		{
+			name:          "BIGSERIAL",
+			table:         NewTable("events").WithKey(BigSerialKey),
+			wantSQL:       `CREATE TABLE IF NOT EXISTS "events" ("id" BIGSERIAL PRIMARY KEY)`,
+			wantKey:       []string{"id"},
+			autoIncrement: true,
+		},
+		{
+			name:          "IDENTITY",
+			table:         NewTable("events").WithKey(IdentityKey),
+			wantSQL:       `CREATE TABLE IF NOT EXISTS "events" ("id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY)`,
+			wantKey:       []string{"id"},
+			autoIncrement: true,
+		},
+		{
+			name:    "UUID",
+			table:   NewTable("events").WithKey(UUIDKey),
+			wantSQL: `CREATE TABLE IF NOT EXISTS "events" ("id" UUID DEFAULT gen_random_uuid() PRIMARY KEY)`,
+			wantKey: []string{"id"},
+		},
+		{
+			name: "identité BY DEFAULT",
+			table: NewTable("tickets").WithoutKey().
+				AddAttribute("number", Integer).NotNull().Identity(GeneratedByDefault).Build().
+				PrimaryKey("number"),
+			wantSQL:       `CREATE TABLE IF NOT EXISTS "tickets" ("number" INTEGER GENERATED BY DEFAULT AS IDENTITY NOT NULL, CONSTRAINT "tickets_pkey" PRIMARY KEY ("number"))`,
+			wantKey:       []string{"number"},
+			autoIncrement: true,
+		},
+		{
+			name: "clé composite",
+			table: NewTable("post_categories").
+				AddAttribute("post_id", Integer).NotNull().Build().
+				AddAttribute("category_id", Integer).NotNull().Build().
+				PrimaryKey("post_id", "category_id"),
+			wantSQL: `CREATE TABLE IF NOT EXISTS "post_categories" ("post_id" INTEGER NOT NULL, "category_id" INTEGER NOT NULL, CONSTRAINT "post_categories_pkey" PRIMARY KEY ("post_id", "category_id"))`,
+			wantKey: []string{"post_id", "category_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.table.BuildSQL(); got != tt.wantSQL {
+				t.Errorf("BuildSQL = %s, attendu %s", got, tt.wantSQL)
+			}
+
+			var key []string
+			for _, attr := range tt.table.GetPrimaryKey() {
+				key = append(key, attr.GetName())
+			}
+			if !reflect.DeepEqual(key, tt.wantKey) {
+				t.Fatalf("GetPrimaryKey = %v, attendu %v", key, tt.wantKey)
+			}
+			if got := tt.table.GetPrimaryKey()[0].IsAutoIncrement(); got != tt.autoIncrement {
+				t.Errorf("IsAutoIncrement = %v, attendu %v", got, tt.autoIncrement)
+			}
+		})
+	}
+}
+
+func TestWithKeyUnknownStrategy(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("panique attendue pour une stratégie inconnue")
+		}
+	}()
+	NewTable("events").WithKey("ULID")
+}
@@ -0,0 +1,428 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"postgo/logging"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationsTable est le nom de la table de suivi des migrations appliquées
+const migrationsTable = "schema_migrations"
+
+// ErrChecksumMismatch indique qu'une migration déjà appliquée a été modifiée depuis
+var ErrChecksumMismatch = errors.New("la migration a été modifiée après son application")
+
+// Migration représente une évolution versionnée du schéma avec son script
+// d'application (Up) et son script d'annulation (Down)
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum retourne l'empreinte SHA-256 du script Up de la migration
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// MigrationStatus décrit l'état d'une migration par rapport à la base de données
+type MigrationStatus struct {
+	Migration Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified indique que le script Up a changé depuis son application
+	Modified bool
+	// Missing indique une migration appliquée en base dont le fichier n'existe plus
+	Missing bool
+}
+
+// appliedMigration représente une ligne de la table schema_migrations
+type appliedMigration struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// LoadMigrations lit les migrations d'un répertoire du système de fichiers
+func LoadMigrations(dir string) ([]Migration, error) {
+	return LoadMigrationsFS(os.DirFS(dir), ".")
+}
+
+// LoadMigrationsFS lit les migrations du répertoire dir d'un fs.FS (par exemple embed.FS).
+// Les fichiers doivent être nommés <version>_<nom>.up.sql et <version>_<nom>.down.sql,
+// les autres fichiers sont ignorés. Le fichier down est optionnel.
+func LoadMigrationsFS(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire le répertoire de migrations %s: %w", dir, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		version, name, err := parseMigrationFileName(strings.TrimSuffix(fileName, "."+direction+".sql"))
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("impossible de lire la migration %s: %w", fileName, err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("la version %d est utilisée par deux migrations: '%s' et '%s'", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+			hasUp[version] = true
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, migration := range byVersion {
+		if !hasUp[version] {
+			return nil, fmt.Errorf("la migration %d_%s n'a pas de fichier .up.sql", version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parseMigrationFileName extrait la version et le nom d'un fichier <version>_<nom>
+func parseMigrationFileName(base string) (int64, string, error) {
+	versionPart, name, found := strings.Cut(base, "_")
+	if !found || name == "" {
+		return 0, "", fmt.Errorf("nom de migration invalide '%s': format attendu <version>_<nom>", base)
+	}
+
+	version, err := strconv.ParseInt(versionPart, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", fmt.Errorf("nom de migration invalide '%s': la version doit être un entier positif", base)
+	}
+
+	return version, name, nil
+}
+
+// Migrator applique et annule des migrations sur une connexion
+type Migrator struct {
+	conn       *Connection
+	migrations []Migration
+}
+
+// NewMigrator crée un migrator pour les migrations données.
+// Les migrations sont triées par version et les doublons sont refusés.
+func NewMigrator(conn *Connection, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("la version %d est utilisée par plusieurs migrations", sorted[i].Version)
+		}
+	}
+
+	return &Migrator{
+		conn:       conn,
+		migrations: sorted,
+	}, nil
+}
+
+// Up applique toutes les migrations en attente, dans l'ordre des versions.
+// Chaque migration est exécutée dans sa propre transaction.
+func (m *Migrator) Up() error {
+	return m.UpContext(context.Background())
+}
+
+// UpContext applique les migrations en attente comme Up en respectant le contexte.
+// Une migration interrompue par le contexte est annulée, les précédentes restent appliquées.
+func (m *Migrator) UpContext(ctx context.Context) error {
+	conn, unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	applied, err := m.loadApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if err := m.verifyChecksums(applied); err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, migration := range m.migrations {
+		if _, done := applied[migration.Version]; done {
+			continue
+		}
+
+		logging.Info.Printf("Application de la migration %d_%s...", migration.Version, migration.Name)
+		err := transaction(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx,
+				fmt.Sprintf(`INSERT INTO "%s" ("version", "name", "checksum") VALUES ($1, $2, $3)`, migrationsTable),
+				migration.Version, migration.Name, migration.Checksum(),
+			)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("échec de la migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		pending++
+	}
+
+	logging.Info.Printf("%d migration(s) appliquée(s)", pending)
+	return nil
+}
+
+// Down annule les steps dernières migrations appliquées, de la plus récente à la plus ancienne
+func (m *Migrator) Down(steps int) error {
+	return m.DownContext(context.Background(), steps)
+}
+
+// DownContext annule les dernières migrations comme Down en respectant le contexte
+func (m *Migrator) DownContext(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("le nombre de migrations à annuler doit être positif")
+	}
+
+	conn, unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	applied, err := m.loadApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if err := m.verifyChecksums(applied); err != nil {
+		return err
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] > versions[j]
+	})
+
+	if steps > len(versions) {
+		steps = len(versions)
+	}
+
+	for _, version := range versions[:steps] {
+		migration, found := m.find(version)
+		if !found {
+			return fmt.Errorf("la migration %d_%s est appliquée mais son fichier est introuvable", version, applied[version].name)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			return fmt.Errorf("la migration %d_%s est irréversible: aucun script down", migration.Version, migration.Name)
+		}
+
+		logging.Info.Printf("Annulation de la migration %d_%s...", migration.Version, migration.Name)
+		err := transaction(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM "%s" WHERE "version" = $1`, migrationsTable), migration.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("échec de l'annulation de la migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	logging.Info.Printf("%d migration(s) annulée(s)", steps)
+	return nil
+}
+
+// Status retourne l'état de chaque migration connue ainsi que celui des migrations
+// appliquées en base dont le fichier a disparu, triés par version
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	return m.StatusContext(context.Background())
+}
+
+// StatusContext retourne l'état des migrations comme Status en respectant le contexte
+func (m *Migrator) StatusContext(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.loadApplied(ctx, m.conn.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if row, done := applied[migration.Version]; done {
+			status.Applied = true
+			status.AppliedAt = row.appliedAt
+			status.Modified = row.checksum != migration.Checksum()
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, row := range applied {
+		if _, found := m.find(version); !found {
+			statuses = append(statuses, MigrationStatus{
+				Migration: Migration{Version: row.version, Name: row.name},
+				Applied:   true,
+				AppliedAt: row.appliedAt,
+				Missing:   true,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Migration.Version < statuses[j].Migration.Version
+	})
+
+	return statuses, nil
+}
+
+// ledger représente ce sur quoi la table de suivi est lue : la connexion dédiée qui porte
+// le verrou des migrations, ou le pool pour Status
+type ledger interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// lock sérialise les exécutions de Up et Down sur la base : deux migrators lancés en même
+// temps (plusieurs instances au déploiement) liraient sinon la même table de suivi et
+// appliqueraient deux fois les mêmes migrations. Le verrou consultatif de session est pris
+// sur une connexion dédiée, qui exécute ensuite la lecture de la table de suivi et les
+// transactions des migrations : aucune autre connexion du pool n'est nécessaire et aucune
+// transaction ne reste ouverte pendant les migrations. La fonction retournée libère le
+// verrou puis rend la connexion au pool.
+func (m *Migrator) lock(ctx context.Context) (*sql.Conn, func(), error) {
+	conn, err := m.conn.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("impossible de verrouiller les migrations: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", migrationsTable); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("impossible de verrouiller les migrations: %w", err)
+	}
+
+	unlock := func() {
+		// Le verrou de session survit au retour de la connexion dans le pool : une connexion
+		// dont le verrou n'a pas pu être libéré est fermée plutôt que réutilisée
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", migrationsTable); err != nil {
+			logging.Warning.Printf("Impossible de libérer le verrou des migrations: %v", err)
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+	return conn, unlock, nil
+}
+
+// ensureTable crée la table de suivi des migrations si elle n'existe pas
+func (m *Migrator) ensureTable(ctx context.Context, conn ledger) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (
+	"version" BIGINT PRIMARY KEY,
+	"name" VARCHAR(255) NOT NULL,
+	"checksum" VARCHAR(64) NOT NULL,
+	"applied_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+)`, migrationsTable)
+
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("impossible de créer la table %s: %w", migrationsTable, err)
+	}
+	return nil
+}
+
+// loadApplied retourne les migrations enregistrées dans la table de suivi, indexées par version
+func (m *Migrator) loadApplied(ctx context.Context, conn ledger) (map[int64]appliedMigration, error) {
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(`SELECT "version", "name", "checksum", "applied_at" FROM "%s"`, migrationsTable))
+	if err != nil {
+		return nil, fmt.Errorf("impossible de lire la table %s: %w", migrationsTable, err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var row appliedMigration
+		if err := rows.Scan(&row.version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[row.version] = row
+	}
+
+	return applied, rows.Err()
+}
+
+// verifyChecksums vérifie que les migrations appliquées n'ont pas été modifiées
+func (m *Migrator) verifyChecksums(applied map[int64]appliedMigration) error {
+	for _, migration := range m.migrations {
+		row, done := applied[migration.Version]
+		if done && row.checksum != migration.Checksum() {
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, ErrChecksumMismatch)
+		}
+	}
+	return nil
+}
+
+// find retourne la migration correspondant à une version
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
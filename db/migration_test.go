@@ -0,0 +1,180 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMigrationFileName(t *testing.T) {
+	tests := []struct {
+		base        string
+		wantVersion int64
+		wantName    string
+		wantErr     string
+	}{
+		{"1_create_users", 1, "create_users", ""},
+		{"20240115093000_add_posts_slug", 20240115093000, "add_posts_slug", ""},
+		{"0002_add_bio", 2, "add_bio", ""},
+		{"create_users", 0, "", "la version doit être un entier positif"},
+		{"3", 0, "", "format attendu <version>_<nom>"},
+		{"3_", 0, "", "format attendu <version>_<nom>"},
+		{"0_init", 0, "", "la version doit être un entier positif"},
+		{"-1_init", 0, "", "la version doit être un entier positif"},
+		{"v1_init", 0, "", "la version doit être un entier positif"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.base, func(t *testing.T) {
+			version, name, err := parseMigrationFileName(tt.base)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tt.wantVersion || name != tt.wantName {
+				t.Errorf("parseMigrationFileName(%q) = %d, %q, attendu %d, %q", tt.base, version, name, tt.wantVersion, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id SERIAL PRIMARY KEY)"},
+		{Version: 2, Name: "add_bio", Up: "ALTER TABLE users ADD COLUMN bio TEXT"},
+	}
+	migrator, err := NewMigrator(nil, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		applied map[int64]appliedMigration
+		wantErr string
+	}{
+		{"aucune appliquée", map[int64]appliedMigration{}, ""},
+		{"empreintes identiques", map[int64]appliedMigration{
+			1: {version: 1, checksum: migrations[0].Checksum()},
+			2: {version: 2, checksum: migrations[1].Checksum()},
+		}, ""},
+		{"fichier disparu", map[int64]appliedMigration{
+			3: {version: 3, checksum: "inconnue"},
+		}, ""},
+		{"script modifié", map[int64]appliedMigration{
+			1: {version: 1, checksum: migrations[0].Checksum()},
+			2: {version: 2, checksum: Migration{Up: "ALTER TABLE users ADD COLUMN bio VARCHAR(255)"}.Checksum()},
+		}, "migration 2_add_bio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrator.verifyChecksums(tt.applied)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("erreur inattendue: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrChecksumMismatch) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erreur = %v, attendu ErrChecksumMismatch pour %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMigratorRejectsDuplicateVersions(t *testing.T) {
+	_, err := NewMigrator(nil, []Migration{{Version: 2, Name: "b"}, {Version: 1, Name: "a"}, {Version: 2, Name: "c"}})
+	if err == nil || !strings.Contains(err.Error(), "la version 2") {
+		t.Fatalf("erreur = %v, attendu un doublon de version", err)
+	}
+}
+
+func TestMigratorLock(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id SERIAL PRIMARY KEY)", Down: "DROP TABLE users"},
+		{Version: 2, Name: "add_bio", Up: "ALTER TABLE users ADD COLUMN bio TEXT"},
+	}
+
+	tests := []struct {
+		name    string
+		fail    string
+		wantErr string
+		want    []string
+	}{
+		{
+			name: "migrations appliquées",
+			want: []string{
+				"1: SELECT pg_advisory_lock(hashtext($1))",
+				"1: CREATE TABLE IF NOT EXISTS",
+				`1: SELECT "version", "name", "checksum", "applied_at" FROM "schema_migrations"`,
+				"1: BEGIN",
+				"1: CREATE TABLE users (id SERIAL PRIMARY KEY)",
+				`1: INSERT INTO "schema_migrations"`,
+				"1: COMMIT",
+				"1: BEGIN",
+				"1: ALTER TABLE users ADD COLUMN bio TEXT",
+				`1: INSERT INTO "schema_migrations"`,
+				"1: COMMIT",
+				"1: SELECT pg_advisory_unlock(hashtext($1))",
+			},
+		},
+		{
+			name:    "migration en échec",
+			fail:    "ADD COLUMN bio",
+			wantErr: "échec de la migration 2_add_bio",
+			want: []string{
+				"1: SELECT pg_advisory_lock(hashtext($1))",
+				"1: CREATE TABLE IF NOT EXISTS",
+				`1: SELECT "version", "name", "checksum", "applied_at" FROM "schema_migrations"`,
+				"1: BEGIN",
+				"1: CREATE TABLE users (id SERIAL PRIMARY KEY)",
+				`1: INSERT INTO "schema_migrations"`,
+				"1: COMMIT",
+				"1: BEGIN",
+				"1: ALTER TABLE users ADD COLUMN bio TEXT",
+				"1: ROLLBACK",
+				"1: SELECT pg_advisory_unlock(hashtext($1))",
+			},
+		},
+		{
+			name:    "verrou indisponible",
+			fail:    "pg_advisory_lock",
+			wantErr: "impossible de verrouiller les migrations",
+			want:    []string{"1: SELECT pg_advisory_lock(hashtext($1))"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, recorder := newRecordingConnection(tt.fail)
+			defer conn.Close()
+			// Une seule connexion suffit : le verrou et les migrations la partagent
+			conn.db.SetMaxOpenConns(1)
+
+			migrator, err := NewMigrator(conn, migrations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = migrator.Up()
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+			}
+
+			got := recorder.statements()
+			if len(got) != len(tt.want) {
+				t.Fatalf("requêtes =\n%s", strings.Join(got, "\n"))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("requête %d = %q, attendu %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package query
+
+import "fmt"
+
+// Selection est une expression qui peut être sélectionnée ou regroupée dans une
+// requête SELECT : une colonne typée, un agrégat ou une expression SQL brute
+type Selection interface {
+	// Expression retourne l'expression SQL telle qu'elle apparaît dans la requête
+	Expression() string
+}
+
+// Expression retourne le nom de la colonne qualifié par sa table, pour l'utiliser
+// comme Selection (SELECT, GROUP BY)
+func (c columnRef) Expression() string {
+	return c.Qualified()
+}
+
+// rawSelection est une expression SQL brute utilisée comme Selection
+type rawSelection string
+
+func (s rawSelection) Expression() string {
+	return string(s)
+}
+
+// Expr retourne une expression SQL brute utilisable dans Select et GroupBy
+// (ex: Expr("date_trunc('month', posts.published_at) AS month"))
+func Expr(sql string) Selection {
+	return rawSelection(sql)
+}
+
+// Aggregate représente une fonction d'agrégat dont le résultat a le type Go T.
+// Les agrégats sont sélectionnés avec SelectQuery.Select et filtrés avec Having.
+type Aggregate[T any] struct {
+	expression string
+	alias      string
+}
+
+// NewAggregate crée un agrégat à partir d'une expression SQL (ex: NewAggregate[float64]("stddev(revenue)"))
+func NewAggregate[T any](expression string) Aggregate[T] {
+	return Aggregate[T]{expression: expression}
+}
+
+// Count retourne l'agrégat COUNT(*), qui compte les lignes de chaque groupe
+func Count() Aggregate[int64] {
+	return NewAggregate[int64]("COUNT(*)")
+}
+
+// As nomme le résultat de l'agrégat (COUNT(*) AS total). Le nom est celui de la colonne
+// lue par Scan, à associer au tag db du champ de destination.
+func (a Aggregate[T]) As(alias string) Aggregate[T] {
+	a.alias = alias
+	return a
+}
+
+// Expression retourne l'agrégat tel qu'il apparaît dans la liste SELECT, avec son nom éventuel
+func (a Aggregate[T]) Expression() string {
+	if a.alias != "" {
+		return a.expression + " AS " + a.alias
+	}
+	return a.expression
+}
+
+// Eq construit la condition agrégat = value, utilisable dans Having
+func (a Aggregate[T]) Eq(value T) Condition {
+	return Eq(a.expression, value)
+}
+
+// Neq construit la condition agrégat <> value
+func (a Aggregate[T]) Neq(value T) Condition {
+	return Neq(a.expression, value)
+}
+
+// Gt construit la condition agrégat > value
+func (a Aggregate[T]) Gt(value T) Condition {
+	return Gt(a.expression, value)
+}
+
+// Gte construit la condition agrégat >= value
+func (a Aggregate[T]) Gte(value T) Condition {
+	return Gte(a.expression, value)
+}
+
+// Lt construit la condition agrégat < value
+func (a Aggregate[T]) Lt(value T) Condition {
+	return Lt(a.expression, value)
+}
+
+// Lte construit la condition agrégat <= value
+func (a Aggregate[T]) Lte(value T) Condition {
+	return Lte(a.expression, value)
+}
+
+// Count retourne l'agrégat COUNT(colonne), qui compte les valeurs non NULL
+func (c columnRef) Count() Aggregate[int64] {
+	return NewAggregate[int64](fmt.Sprintf("COUNT(%s)", c.Qualified()))
+}
+
+// CountDistinct retourne l'agrégat COUNT(DISTINCT colonne), qui compte les valeurs distinctes non NULL
+func (c columnRef) CountDistinct() Aggregate[int64] {
+	return NewAggregate[int64](fmt.Sprintf("COUNT(DISTINCT %s)", c.Qualified()))
+}
+
+// Sum retourne l'agrégat SUM(colonne). La somme vaut NULL pour un groupe sans valeur :
+// lisez-la dans un pointeur si le groupe peut être vide.
+func (c Column[T]) Sum() Aggregate[T] {
+	return NewAggregate[T](fmt.Sprintf("SUM(%s)", c.Qualified()))
+}
+
+// Avg retourne l'agrégat AVG(colonne), lu comme un float64
+func (c Column[T]) Avg() Aggregate[float64] {
+	return NewAggregate[float64](fmt.Sprintf("AVG(%s)", c.Qualified()))
+}
+
+// Min retourne l'agrégat MIN(colonne)
+func (c Column[T]) Min() Aggregate[T] {
+	return NewAggregate[T](fmt.Sprintf("MIN(%s)", c.Qualified()))
+}
+
+// Max retourne l'agrégat MAX(colonne)
+func (c Column[T]) Max() Aggregate[T] {
+	return NewAggregate[T](fmt.Sprintf("MAX(%s)", c.Qualified()))
+}
+
+// ArrayAgg retourne l'agrégat array_agg(colonne), qui rassemble les valeurs du groupe
+// dans un tableau lu par pq.Array
+func (c Column[T]) ArrayAgg() Aggregate[[]T] {
+	return NewAggregate[[]T](fmt.Sprintf("array_agg(%s)", c.Qualified()))
+}
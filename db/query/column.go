@@ -0,0 +1,216 @@
+package query
+
+import (
+	"github.com/lib/pq"
+)
+
+// Column représente une colonne d'une table dont les valeurs ont le type Go T.
+// Le code généré expose une Column par colonne (ex: generated.Users.Email) afin
+// de construire des conditions vérifiées à la compilation. Les conditions
+// référencent la colonne qualifiée par sa table (ex: users.email).
+type Column[T any] struct {
+	columnRef
+}
+
+// columnRef désigne une colonne d'une table
+type columnRef struct {
+	table string
+	name  string
+}
+
+// NewColumn crée une colonne typée de la table donnée
+func NewColumn[T any](table, name string) Column[T] {
+	return Column[T]{columnRef{table: table, name: name}}
+}
+
+// Name retourne le nom de la colonne
+func (c columnRef) Name() string {
+	return c.name
+}
+
+// Table retourne le nom de la table de la colonne
+func (c columnRef) Table() string {
+	return c.table
+}
+
+// Qualified retourne le nom de la colonne qualifié par sa table (ex: users.email)
+func (c columnRef) Qualified() string {
+	return c.table + "." + c.name
+}
+
+// Alias retourne la même colonne qualifiée par l'alias d'une table jointe
+// (ex: Users.Name.Alias("author") désigne author.name)
+func (c Column[T]) Alias(alias string) Column[T] {
+	return NewColumn[T](alias, c.name)
+}
+
+// EqColumn construit la condition colonne = other entre deux colonnes de même type,
+// typiquement la condition ON d'une jointure
+func (c Column[T]) EqColumn(other Column[T]) Condition {
+	return EqColumns(c.Qualified(), other.Qualified())
+}
+
+// Eq construit la condition colonne = value
+func (c Column[T]) Eq(value T) Condition {
+	return Eq(c.Qualified(), value)
+}
+
+// Neq construit la condition colonne <> value
+func (c Column[T]) Neq(value T) Condition {
+	return Neq(c.Qualified(), value)
+}
+
+// Gt construit la condition colonne > value
+func (c Column[T]) Gt(value T) Condition {
+	return Gt(c.Qualified(), value)
+}
+
+// Gte construit la condition colonne >= value
+func (c Column[T]) Gte(value T) Condition {
+	return Gte(c.Qualified(), value)
+}
+
+// Lt construit la condition colonne < value
+func (c Column[T]) Lt(value T) Condition {
+	return Lt(c.Qualified(), value)
+}
+
+// Lte construit la condition colonne <= value
+func (c Column[T]) Lte(value T) Condition {
+	return Lte(c.Qualified(), value)
+}
+
+// Between construit la condition colonne BETWEEN low AND high (bornes incluses)
+func (c Column[T]) Between(low, high T) Condition {
+	return Between(c.Qualified(), low, high)
+}
+
+// In construit la condition colonne IN (values...)
+func (c Column[T]) In(values ...T) Condition {
+	return In(c.Qualified(), toInterfaces(values)...)
+}
+
+// NotIn construit la condition colonne NOT IN (values...)
+func (c Column[T]) NotIn(values ...T) Condition {
+	return NotIn(c.Qualified(), toInterfaces(values)...)
+}
+
+// IsNull construit la condition colonne IS NULL
+func (c Column[T]) IsNull() Condition {
+	return IsNull(c.Qualified())
+}
+
+// IsNotNull construit la condition colonne IS NOT NULL
+func (c Column[T]) IsNotNull() Condition {
+	return IsNotNull(c.Qualified())
+}
+
+// StringColumn est une colonne textuelle, qui accepte en plus les conditions LIKE
+type StringColumn struct {
+	Column[string]
+}
+
+// NewStringColumn crée une colonne textuelle de la table donnée
+func NewStringColumn(table, name string) StringColumn {
+	return StringColumn{Column: NewColumn[string](table, name)}
+}
+
+// Alias retourne la même colonne qualifiée par l'alias d'une table jointe
+func (c StringColumn) Alias(alias string) StringColumn {
+	return NewStringColumn(alias, c.name)
+}
+
+// Like construit la condition colonne LIKE pattern
+func (c StringColumn) Like(pattern string) Condition {
+	return Like(c.Qualified(), pattern)
+}
+
+// ILike construit la condition colonne ILIKE pattern (insensible à la casse)
+func (c StringColumn) ILike(pattern string) Condition {
+	return ILike(c.Qualified(), pattern)
+}
+
+// BoolColumn est une colonne booléenne, qui accepte en plus IS TRUE et IS FALSE
+type BoolColumn struct {
+	Column[bool]
+}
+
+// NewBoolColumn crée une colonne booléenne de la table donnée
+func NewBoolColumn(table, name string) BoolColumn {
+	return BoolColumn{Column: NewColumn[bool](table, name)}
+}
+
+// Alias retourne la même colonne qualifiée par l'alias d'une table jointe
+func (c BoolColumn) Alias(alias string) BoolColumn {
+	return NewBoolColumn(alias, c.name)
+}
+
+// IsTrue construit la condition colonne IS TRUE (fausse pour NULL)
+func (c BoolColumn) IsTrue() Condition {
+	return IsTrue(c.Qualified())
+}
+
+// IsFalse construit la condition colonne IS FALSE (fausse pour NULL)
+func (c BoolColumn) IsFalse() Condition {
+	return IsFalse(c.Qualified())
+}
+
+// ArrayColumn est une colonne tableau PostgreSQL dont les éléments ont le type Go E.
+// Les tableaux sont transmis en paramètres liés par pq.Array.
+type ArrayColumn[E any] struct {
+	columnRef
+}
+
+// NewArrayColumn crée une colonne tableau de la table donnée
+func NewArrayColumn[E any](table, name string) ArrayColumn[E] {
+	return ArrayColumn[E]{columnRef{table: table, name: name}}
+}
+
+// Alias retourne la même colonne qualifiée par l'alias d'une table jointe
+func (c ArrayColumn[E]) Alias(alias string) ArrayColumn[E] {
+	return NewArrayColumn[E](alias, c.name)
+}
+
+// Eq construit la condition colonne = values (mêmes éléments, dans le même ordre)
+func (c ArrayColumn[E]) Eq(values []E) Condition {
+	return Eq(c.Qualified(), pq.Array(values))
+}
+
+// Contains construit la condition colonne @> values : le tableau contient tous les éléments
+func (c ArrayColumn[E]) Contains(values ...E) Condition {
+	return ArrayContains(c.Qualified(), values)
+}
+
+// ContainedBy construit la condition colonne <@ values : tous les éléments du tableau font partie de values
+func (c ArrayColumn[E]) ContainedBy(values ...E) Condition {
+	return ArrayContainedBy(c.Qualified(), values)
+}
+
+// Overlaps construit la condition colonne && values : au moins un élément en commun
+func (c ArrayColumn[E]) Overlaps(values ...E) Condition {
+	return ArrayOverlaps(c.Qualified(), values)
+}
+
+// Has construit la condition value = ANY(colonne) : le tableau contient l'élément
+func (c ArrayColumn[E]) Has(value E) Condition {
+	return ArrayHas(c.Qualified(), value)
+}
+
+// IsNull construit la condition colonne IS NULL
+func (c ArrayColumn[E]) IsNull() Condition {
+	return IsNull(c.Qualified())
+}
+
+// IsNotNull construit la condition colonne IS NOT NULL
+func (c ArrayColumn[E]) IsNotNull() Condition {
+	return IsNotNull(c.Qualified())
+}
+
+// toInterfaces convertit une liste typée en liste de valeurs liées
+func toInterfaces[T any](values []T) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -12,14 +12,14 @@ type QueryBuilder interface {
 
 // BaseQuery contient les champs communs à toutes les requêtes
 type BaseQuery struct {
-	conditions []string
+	conditions []Condition
 	orderBy    []string
 	limit      *int
 	offset     *int
 }
 
-// Where ajoute une condition WHERE à la requête
-func (q *BaseQuery) Where(condition string) *BaseQuery {
+// Where ajoute une condition WHERE à la requête, combinée aux précédentes avec AND
+func (q *BaseQuery) Where(condition Condition) *BaseQuery {
 	q.conditions = append(q.conditions, condition)
 	return q
 }
@@ -42,12 +42,13 @@ func (q *BaseQuery) Offset(offset int) *BaseQuery {
 	return q
 }
 
-// buildCommonClauses construit les clauses communes (WHERE, ORDER BY, LIMIT, OFFSET)
-func (q *BaseQuery) buildCommonClauses() string {
+// buildCommonClauses construit les clauses communes (WHERE, ORDER BY, LIMIT, OFFSET).
+// Les valeurs des conditions sont ajoutées à args à la suite des valeurs existantes.
+func (q *BaseQuery) buildCommonClauses(args *arguments) string {
 	var clauses []string
 
-	if len(q.conditions) > 0 {
-		clauses = append(clauses, "WHERE "+strings.Join(q.conditions, " AND "))
+	if where := buildWhere(q.conditions, args); where != "" {
+		clauses = append(clauses, where)
 	}
 
 	if len(q.orderBy) > 0 {
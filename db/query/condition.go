@@ -0,0 +1,311 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Condition représente une expression booléenne utilisable dans une clause WHERE.
+// Les valeurs ne sont jamais concaténées au SQL : elles sont transmises en paramètres
+// liés et les placeholders ($1, $2...) sont numérotés lors de la construction de la
+// requête complète, à la suite des valeurs déjà utilisées (SET d'un UPDATE par exemple).
+type Condition interface {
+	build(args *arguments) string
+}
+
+// arguments accumule les valeurs liées d'une requête et attribue leurs placeholders
+type arguments struct {
+	values []interface{}
+}
+
+// add ajoute une valeur liée et retourne son placeholder
+func (a *arguments) add(value interface{}) string {
+	a.values = append(a.values, value)
+	return fmt.Sprintf("$%d", len(a.values))
+}
+
+// comparison représente une comparaison entre une colonne et une valeur
+type comparison struct {
+	column   string
+	operator string
+	value    interface{}
+}
+
+func (c comparison) build(args *arguments) string {
+	return fmt.Sprintf("%s %s %s", c.column, c.operator, args.add(c.value))
+}
+
+// Eq construit la condition column = value
+func Eq(column string, value interface{}) Condition {
+	return comparison{column: column, operator: "=", value: value}
+}
+
+// Neq construit la condition column <> value
+func Neq(column string, value interface{}) Condition {
+	return comparison{column: column, operator: "<>", value: value}
+}
+
+// Gt construit la condition column > value
+func Gt(column string, value interface{}) Condition {
+	return comparison{column: column, operator: ">", value: value}
+}
+
+// Gte construit la condition column >= value
+func Gte(column string, value interface{}) Condition {
+	return comparison{column: column, operator: ">=", value: value}
+}
+
+// Lt construit la condition column < value
+func Lt(column string, value interface{}) Condition {
+	return comparison{column: column, operator: "<", value: value}
+}
+
+// Lte construit la condition column <= value
+func Lte(column string, value interface{}) Condition {
+	return comparison{column: column, operator: "<=", value: value}
+}
+
+// Like construit la condition column LIKE pattern
+func Like(column string, pattern string) Condition {
+	return comparison{column: column, operator: "LIKE", value: pattern}
+}
+
+// ILike construit la condition column ILIKE pattern (insensible à la casse)
+func ILike(column string, pattern string) Condition {
+	return comparison{column: column, operator: "ILIKE", value: pattern}
+}
+
+// inCondition représente la condition column IN (...) ou NOT IN (...)
+type inCondition struct {
+	column string
+	values []interface{}
+	negate bool
+}
+
+func (c inCondition) build(args *arguments) string {
+	// Une liste vide ne correspond à aucune ligne (IN) ou à toutes (NOT IN)
+	if len(c.values) == 0 {
+		if c.negate {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+
+	placeholders := make([]string, len(c.values))
+	for i, value := range c.values {
+		placeholders[i] = args.add(value)
+	}
+
+	operator := "IN"
+	if c.negate {
+		operator = "NOT IN"
+	}
+	return fmt.Sprintf("%s %s (%s)", c.column, operator, strings.Join(placeholders, ", "))
+}
+
+// In construit la condition column IN (values...)
+func In(column string, values ...interface{}) Condition {
+	return inCondition{column: column, values: values}
+}
+
+// NotIn construit la condition column NOT IN (values...)
+func NotIn(column string, values ...interface{}) Condition {
+	return inCondition{column: column, values: values, negate: true}
+}
+
+// nullCondition représente la condition column IS NULL ou IS NOT NULL
+type nullCondition struct {
+	column string
+	negate bool
+}
+
+func (c nullCondition) build(args *arguments) string {
+	if c.negate {
+		return c.column + " IS NOT NULL"
+	}
+	return c.column + " IS NULL"
+}
+
+// IsNull construit la condition column IS NULL
+func IsNull(column string) Condition {
+	return nullCondition{column: column}
+}
+
+// IsNotNull construit la condition column IS NOT NULL
+func IsNotNull(column string) Condition {
+	return nullCondition{column: column, negate: true}
+}
+
+// booleanCondition représente la condition column IS TRUE ou IS FALSE
+type booleanCondition struct {
+	column string
+	value  bool
+}
+
+func (c booleanCondition) build(args *arguments) string {
+	if c.value {
+		return c.column + " IS TRUE"
+	}
+	return c.column + " IS FALSE"
+}
+
+// IsTrue construit la condition column IS TRUE (fausse lorsque la colonne est NULL)
+func IsTrue(column string) Condition {
+	return booleanCondition{column: column, value: true}
+}
+
+// IsFalse construit la condition column IS FALSE (fausse lorsque la colonne est NULL)
+func IsFalse(column string) Condition {
+	return booleanCondition{column: column, value: false}
+}
+
+// betweenCondition représente la condition column BETWEEN low AND high
+type betweenCondition struct {
+	column string
+	low    interface{}
+	high   interface{}
+}
+
+func (c betweenCondition) build(args *arguments) string {
+	return fmt.Sprintf("%s BETWEEN %s AND %s", c.column, args.add(c.low), args.add(c.high))
+}
+
+// Between construit la condition column BETWEEN low AND high (bornes incluses)
+func Between(column string, low, high interface{}) Condition {
+	return betweenCondition{column: column, low: low, high: high}
+}
+
+// arrayCondition représente une condition sur une colonne tableau. Le tableau
+// comparé est transmis en un seul paramètre lié, converti par pq.Array.
+type arrayCondition struct {
+	format string
+	column string
+	value  interface{}
+}
+
+func (c arrayCondition) build(args *arguments) string {
+	return fmt.Sprintf(c.format, c.column, args.add(c.value))
+}
+
+// ArrayContains construit la condition column @> values : le tableau contient tous les éléments
+func ArrayContains(column string, values interface{}) Condition {
+	return arrayCondition{format: "%s @> %s", column: column, value: pq.Array(values)}
+}
+
+// ArrayContainedBy construit la condition column <@ values : tous les éléments du tableau font partie de values
+func ArrayContainedBy(column string, values interface{}) Condition {
+	return arrayCondition{format: "%s <@ %s", column: column, value: pq.Array(values)}
+}
+
+// ArrayOverlaps construit la condition column && values : le tableau a au moins un élément en commun avec values
+func ArrayOverlaps(column string, values interface{}) Condition {
+	return arrayCondition{format: "%s && %s", column: column, value: pq.Array(values)}
+}
+
+// ArrayHas construit la condition value = ANY(column) : le tableau contient l'élément value
+func ArrayHas(column string, value interface{}) Condition {
+	return arrayCondition{format: "%[2]s = ANY(%[1]s)", column: column, value: value}
+}
+
+// EqAny construit la condition column = ANY(values) : équivalente à In, mais la liste
+// est transmise en un seul paramètre lié quelle que soit sa taille
+func EqAny(column string, values interface{}) Condition {
+	return arrayCondition{format: "%s = ANY(%s)", column: column, value: pq.Array(values)}
+}
+
+// logicalCondition combine plusieurs conditions avec AND ou OR
+type logicalCondition struct {
+	operator   string
+	conditions []Condition
+}
+
+func (c logicalCondition) build(args *arguments) string {
+	// Une conjonction vide est toujours vraie, une disjonction vide toujours fausse
+	if len(c.conditions) == 0 {
+		if c.operator == "AND" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+
+	if len(c.conditions) == 1 {
+		return c.conditions[0].build(args)
+	}
+
+	parts := make([]string, len(c.conditions))
+	for i, condition := range c.conditions {
+		parts[i] = condition.build(args)
+	}
+	return "(" + strings.Join(parts, " "+c.operator+" ") + ")"
+}
+
+// And combine des conditions qui doivent toutes être vraies
+func And(conditions ...Condition) Condition {
+	return logicalCondition{operator: "AND", conditions: conditions}
+}
+
+// Or combine des conditions dont au moins une doit être vraie
+func Or(conditions ...Condition) Condition {
+	return logicalCondition{operator: "OR", conditions: conditions}
+}
+
+// notCondition représente la négation d'une condition
+type notCondition struct {
+	condition Condition
+}
+
+func (c notCondition) build(args *arguments) string {
+	return "NOT (" + c.condition.build(args) + ")"
+}
+
+// Not construit la négation d'une condition
+func Not(condition Condition) Condition {
+	return notCondition{condition: condition}
+}
+
+// rawCondition représente un fragment SQL écrit à la main avec des valeurs liées
+type rawCondition struct {
+	sql    string
+	values []interface{}
+}
+
+func (c rawCondition) build(args *arguments) string {
+	var result strings.Builder
+	next := 0
+	for _, char := range c.sql {
+		if char == '?' && next < len(c.values) {
+			result.WriteString(args.add(c.values[next]))
+			next++
+			continue
+		}
+		result.WriteRune(char)
+	}
+	return "(" + result.String() + ")"
+}
+
+// Raw construit une condition à partir d'un fragment SQL pour les cas non couverts
+// par les autres constructeurs. Chaque "?" est remplacé, dans l'ordre, par le
+// placeholder d'une des valeurs, qui restent transmises en paramètres liés.
+// Le fragment ne doit jamais contenir de données fournies par l'utilisateur.
+func Raw(sql string, values ...interface{}) Condition {
+	return rawCondition{sql: sql, values: values}
+}
+
+// buildWhere construit la clause WHERE combinant les conditions avec AND
+func buildWhere(conditions []Condition, args *arguments) string {
+	return buildClause("WHERE", conditions, args)
+}
+
+// buildClause construit une clause (WHERE, HAVING) combinant les conditions avec AND
+func buildClause(keyword string, conditions []Condition, args *arguments) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	parts := make([]string, len(conditions))
+	for i, condition := range conditions {
+		parts[i] = condition.build(args)
+	}
+	return keyword + " " + strings.Join(parts, " AND ")
+}
@@ -0,0 +1,70 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConditionPlaceholders(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []Condition
+		want       string
+		wantValues []interface{}
+	}{
+		{
+			name:       "conditions successives",
+			conditions: []Condition{Eq("a", 1), Gt("b", 2)},
+			want:       "WHERE a = $1 AND b > $2",
+			wantValues: []interface{}{1, 2},
+		},
+		{
+			name:       "And et Or imbriqués",
+			conditions: []Condition{Or(Eq("a", 1), And(Eq("b", 2), In("c", 3, 4)), Between("d", 5, 6))},
+			want:       "WHERE (a = $1 OR (b = $2 AND c IN ($3, $4)) OR d BETWEEN $5 AND $6)",
+			wantValues: []interface{}{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:       "Raw au milieu d'un Or",
+			conditions: []Condition{Eq("a", 1), Or(Raw("lower(b) = ? OR c > ?", "x", 2), Not(Eq("d", 3)))},
+			want:       "WHERE a = $1 AND ((lower(b) = $2 OR c > $3) OR NOT (d = $4))",
+			wantValues: []interface{}{1, "x", 2, 3},
+		},
+		{
+			name:       "Raw sans valeur pour chaque ?",
+			conditions: []Condition{Raw("a ? 'key'"), Raw("b = ? AND c = ?", 1)},
+			want:       "WHERE (a ? 'key') AND (b = $1 AND c = ?)",
+			wantValues: []interface{}{1},
+		},
+		{
+			name:       "conditions sans valeur",
+			conditions: []Condition{IsNull("a"), In("b"), Or(), And(Eq("c", 1))},
+			want:       "WHERE a IS NULL AND FALSE AND FALSE AND c = $1",
+			wantValues: []interface{}{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := &arguments{}
+			if got := buildWhere(tt.conditions, args); got != tt.want {
+				t.Errorf("clause =\n%s\nattendu\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(args.values, tt.wantValues) {
+				t.Errorf("valeurs = %v, attendu %v", args.values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestConditionPlaceholdersAfterSet(t *testing.T) {
+	q := NewUpdateQuery("users").AddColumn("name").AddValue("Ana").AddColumn("age").AddValue(30).
+		Where(Or(Eq("id", 1), Raw("email = ?", "a@b.c")))
+
+	want := "UPDATE users SET name = $1, age = $2 WHERE (id = $3 OR (email = $4))"
+	if got := q.Build(); got != want {
+		t.Errorf("requête =\n%s\nattendu\n%s", got, want)
+	}
+	if values := q.GetValues(); !reflect.DeepEqual(values, []interface{}{"Ana", 30, 1, "a@b.c"}) {
+		t.Errorf("valeurs = %v", values)
+	}
+}
@@ -1,11 +1,14 @@
 package query
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 )
 
 type DeleteQuery struct {
 	BaseQuery
+	returningClause
 	table string
 }
 
@@ -15,22 +18,70 @@ func NewDeleteQuery(table string) *DeleteQuery {
 	}
 }
 
-func (q *DeleteQuery) AddCondition(condition string) *DeleteQuery {
+func (q *DeleteQuery) AddCondition(condition Condition) *DeleteQuery {
+	q.conditions = append(q.conditions, condition)
+	return q
+}
+
+// Where ajoute une condition WHERE à la requête, combinée aux précédentes avec AND
+func (q *DeleteQuery) Where(condition Condition) *DeleteQuery {
 	q.conditions = append(q.conditions, condition)
 	return q
 }
 
 func (q *DeleteQuery) Build() string {
+	query, _ := q.build()
+	return query
+}
+
+// build construit la requête SQL et la liste des valeurs liées
+func (q *DeleteQuery) build() (string, []interface{}) {
+	args := &arguments{}
 	query := "DELETE FROM " + q.table
-	commonClauses := q.buildCommonClauses()
+	commonClauses := q.buildCommonClauses(args)
 	if commonClauses != "" {
 		query += " " + commonClauses
 	}
-	return query
+	if returning := q.buildReturning(); returning != "" {
+		query += " " + returning
+	}
+	return query, args.values
 }
 
-func (q *DeleteQuery) Execute(db *sql.DB) error {
-	query := q.Build()
-	_, err := db.Exec(query)
+// Returning définit les colonnes retournées pour chaque ligne supprimée (RETURNING).
+// Un nouvel appel remplace les colonnes précédentes.
+func (q *DeleteQuery) Returning(columns ...string) *DeleteQuery {
+	q.setReturning(columns)
+	return q
+}
+
+func (q *DeleteQuery) Execute(exec Executor) error {
+	return q.ExecuteContext(context.Background(), exec)
+}
+
+// ExecuteContext exécute la requête en respectant l'annulation et l'échéance du contexte
+func (q *DeleteQuery) ExecuteContext(ctx context.Context, exec Executor) error {
+	query, values := q.build()
+	_, err := exec.ExecContext(ctx, query, values...)
 	return err
 }
+
+// ExecuteReturning exécute la suppression et retourne les lignes de la clause RETURNING
+func (q *DeleteQuery) ExecuteReturning(exec Executor) (*sql.Rows, error) {
+	return q.ExecuteReturningContext(context.Background(), exec)
+}
+
+// ExecuteReturningContext exécute la suppression comme ExecuteReturning en respectant le contexte
+func (q *DeleteQuery) ExecuteReturningContext(ctx context.Context, exec Executor) (*sql.Rows, error) {
+	if len(q.returning) == 0 {
+		return nil, fmt.Errorf("aucune colonne RETURNING définie pour la suppression dans %s", q.table)
+	}
+	query, values := q.build()
+	return exec.QueryContext(ctx, query, values...)
+}
+
+// GetValues retourne les valeurs liées des conditions, dans l'ordre des placeholders
+func (q *DeleteQuery) GetValues() []interface{} {
+	_, values := q.build()
+	return values
+}
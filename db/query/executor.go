@@ -0,0 +1,18 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Executor représente tout ce qui peut exécuter une requête SQL.
+// *sql.DB, *sql.Tx et *db.Connection l'implémentent, ce qui permet d'exécuter
+// les mêmes requêtes dans ou hors d'une transaction.
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
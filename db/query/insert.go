@@ -1,15 +1,19 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 )
 
 type InsertQuery struct {
-	table   string
-	columns []string
-	values  []interface{} // Changé en interface{} pour supporter tous types
+	returningClause
+	conflict conflictClause
+	table    string
+	columns  []string
+	values   []interface{} // Changé en interface{} pour supporter tous types
 }
 
 func NewInsertQuery(table string) *InsertQuery {
@@ -23,6 +27,15 @@ func (q *InsertQuery) AddColumn(column string) *InsertQuery {
 	return q
 }
 
+// RemoveColumn retire une colonne et sa valeur de la requête
+func (q *InsertQuery) RemoveColumn(column string) *InsertQuery {
+	if i := slices.Index(q.columns, column); i >= 0 {
+		q.columns = slices.Delete(q.columns, i, i+1)
+		q.values = slices.Delete(q.values, i, i+1)
+	}
+	return q
+}
+
 // AddValue accepte maintenant n'importe quel type
 func (q *InsertQuery) AddValue(value interface{}) *InsertQuery {
 	q.values = append(q.values, value)
@@ -36,35 +49,85 @@ func (q *InsertQuery) AddStringValue(value string) *InsertQuery {
 }
 
 func (q *InsertQuery) Build() string {
+	query, _ := q.build()
+	return query
+}
+
+// build construit la requête SQL et la liste des valeurs liées.
+// Les valeurs insérées occupent les premiers placeholders, celles du
+// ON CONFLICT ... DO UPDATE SET sont numérotées à leur suite.
+func (q *InsertQuery) build() (string, []interface{}) {
 	if len(q.columns) != len(q.values) {
 		panic("Le nombre de colonnes doit être égal au nombre de valeurs")
 	}
 
-	columnsList := strings.Join(q.columns, ", ")
-
 	// Créer des placeholders ($1, $2, etc.) pour PostgreSQL
+	args := &arguments{}
 	placeholders := make([]string, len(q.values))
-	for i := range placeholders {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	for i, value := range q.values {
+		placeholders[i] = args.add(value)
+	}
+
+	// Sans colonne, chaque colonne reçoit sa valeur par défaut
+	query := fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", q.table)
+	if len(q.columns) > 0 {
+		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", q.table, strings.Join(q.columns, ", "), strings.Join(placeholders, ", "))
+	}
+	if conflict := q.conflict.buildConflict(args); conflict != "" {
+		query += " " + conflict
+	}
+	if returning := q.buildReturning(); returning != "" {
+		query += " " + returning
 	}
-	placeholdersList := strings.Join(placeholders, ", ")
+	return query, args.values
+}
+
+// Returning définit les colonnes retournées par l'insertion (RETURNING), par exemple
+// pour récupérer l'id SERIAL généré. Un nouvel appel remplace les colonnes précédentes.
+func (q *InsertQuery) Returning(columns ...string) *InsertQuery {
+	q.setReturning(columns)
+	return q
+}
 
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", q.table, columnsList, placeholdersList)
+func (q *InsertQuery) Execute(exec Executor) (sql.Result, error) {
+	return q.ExecuteContext(context.Background(), exec)
 }
 
-func (q *InsertQuery) Execute(db *sql.DB) (sql.Result, error) {
-	query := q.Build()
+// ExecuteContext exécute la requête en respectant l'annulation et l'échéance du contexte
+func (q *InsertQuery) ExecuteContext(ctx context.Context, exec Executor) (sql.Result, error) {
+	query, values := q.build()
 	fmt.Printf("Exécution de la requête: %s\n", query)
-	fmt.Printf("Avec les valeurs: %v\n", q.values)
+	fmt.Printf("Avec les valeurs: %v\n", values)
 
-	result, err := db.Exec(query, q.values...)
+	result, err := exec.ExecContext(ctx, query, values...)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-// GetValues retourne les valeurs de la requête (utile pour le générateur)
+// ExecuteReturning exécute l'insertion et retourne les lignes de la clause RETURNING
+func (q *InsertQuery) ExecuteReturning(exec Executor) (*sql.Rows, error) {
+	return q.ExecuteReturningContext(context.Background(), exec)
+}
+
+// ExecuteReturningContext exécute l'insertion comme ExecuteReturning en respectant le contexte
+func (q *InsertQuery) ExecuteReturningContext(ctx context.Context, exec Executor) (*sql.Rows, error) {
+	if len(q.returning) == 0 {
+		return nil, fmt.Errorf("aucune colonne RETURNING définie pour l'insertion dans %s", q.table)
+	}
+	query, values := q.build()
+	return exec.QueryContext(ctx, query, values...)
+}
+
+// GetValues retourne les valeurs liées de la requête (valeurs insérées puis
+// celles du ON CONFLICT), dans l'ordre des placeholders (utile pour le générateur)
 func (q *InsertQuery) GetValues() []interface{} {
-	return q.values
+	_, values := q.build()
+	return values
+}
+
+// GetColumns retourne les colonnes insérées par la requête
+func (q *InsertQuery) GetColumns() []string {
+	return q.columns
 }
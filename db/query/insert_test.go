@@ -0,0 +1,48 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertDefaultValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      *InsertQuery
+		want       string
+		wantValues []interface{}
+	}{
+		{
+			name:       "colonnes fournies",
+			query:      NewInsertQuery("posts").AddColumn("title").AddValue("Bonjour"),
+			want:       "INSERT INTO posts (title) VALUES ($1)",
+			wantValues: []interface{}{"Bonjour"},
+		},
+		{
+			name:  "toutes les colonnes par défaut",
+			query: NewInsertQuery("posts"),
+			want:  "INSERT INTO posts DEFAULT VALUES",
+		},
+		{
+			name:  "par défaut avec RETURNING",
+			query: NewInsertQuery("posts").Returning("id", "created_at"),
+			want:  "INSERT INTO posts DEFAULT VALUES RETURNING id, created_at",
+		},
+		{
+			name:  "par défaut avec ON CONFLICT",
+			query: NewInsertQuery("posts").OnConflict().DoNothing(),
+			want:  "INSERT INTO posts DEFAULT VALUES ON CONFLICT DO NOTHING",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, values := tt.query.build()
+			if query != tt.want {
+				t.Errorf("requête = %s, attendu %s", query, tt.want)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("valeurs = %v, attendu %v", values, tt.wantValues)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package query
+
+import "fmt"
+
+// JoinKind représente le type d'une jointure
+type JoinKind string
+
+const (
+	JoinInner JoinKind = "INNER JOIN"
+	JoinLeft  JoinKind = "LEFT JOIN"
+	JoinRight JoinKind = "RIGHT JOIN"
+	JoinFull  JoinKind = "FULL JOIN"
+)
+
+// join représente une table jointe à la requête, avec son alias éventuel et sa condition ON
+type join struct {
+	kind  JoinKind
+	table string
+	alias string
+	on    Condition
+}
+
+// build retourne la clause de jointure. Les valeurs liées de la condition ON sont
+// numérotées avant celles du WHERE.
+func (j join) build(args *arguments) string {
+	clause := fmt.Sprintf("%s %s", j.kind, j.table)
+	if j.alias != "" {
+		clause += " AS " + j.alias
+	}
+	return clause + " ON " + j.on.build(args)
+}
+
+// columnComparison représente une comparaison entre deux colonnes, sans valeur liée
+type columnComparison struct {
+	left     string
+	operator string
+	right    string
+}
+
+func (c columnComparison) build(args *arguments) string {
+	return fmt.Sprintf("%s %s %s", c.left, c.operator, c.right)
+}
+
+// EqColumns construit la condition left = right entre deux colonnes, typiquement
+// la condition ON d'une jointure (ex: EqColumns("users.id", "posts.author_id"))
+func EqColumns(left, right string) Condition {
+	return columnComparison{left: left, operator: "=", right: right}
+}
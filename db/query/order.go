@@ -0,0 +1,77 @@
+package query
+
+// SortDirection représente le sens d'un tri ORDER BY
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "ASC"
+	Descending SortDirection = "DESC"
+)
+
+// NullsOrder place les valeurs NULL avant ou après les autres dans un tri.
+// Par défaut PostgreSQL les place en dernier en ASC et en premier en DESC.
+type NullsOrder string
+
+const (
+	NullsFirst NullsOrder = "NULLS FIRST"
+	NullsLast  NullsOrder = "NULLS LAST"
+)
+
+// Ordering représente un critère de tri d'une clause ORDER BY. Il est construit par
+// Asc et Desc, ou par les méthodes du même nom des colonnes typées et des agrégats.
+type Ordering struct {
+	expression string
+	direction  SortDirection
+	nulls      NullsOrder
+}
+
+// Asc trie par ordre croissant sur une colonne ou une expression
+func Asc(expression string) Ordering {
+	return Ordering{expression: expression, direction: Ascending}
+}
+
+// Desc trie par ordre décroissant sur une colonne ou une expression
+func Desc(expression string) Ordering {
+	return Ordering{expression: expression, direction: Descending}
+}
+
+// NullsFirst place les valeurs NULL en tête du tri
+func (o Ordering) NullsFirst() Ordering {
+	o.nulls = NullsFirst
+	return o
+}
+
+// NullsLast place les valeurs NULL en fin de tri
+func (o Ordering) NullsLast() Ordering {
+	o.nulls = NullsLast
+	return o
+}
+
+// build retourne le critère tel qu'il apparaît dans la clause ORDER BY
+func (o Ordering) build() string {
+	clause := o.expression + " " + string(o.direction)
+	if o.nulls != "" {
+		clause += " " + string(o.nulls)
+	}
+	return clause
+}
+
+// Asc trie par ordre croissant sur la colonne
+func (c columnRef) Asc() Ordering {
+	return Asc(c.Qualified())
+}
+
+// Desc trie par ordre décroissant sur la colonne
+func (c columnRef) Desc() Ordering {
+	return Desc(c.Qualified())
+}
+
+// Asc trie les groupes par ordre croissant de l'agrégat
+func (a Aggregate[T]) Asc() Ordering {
+	return Asc(a.expression)
+}
+
+// Desc trie les groupes par ordre décroissant de l'agrégat
+func (a Aggregate[T]) Desc() Ordering {
+	return Desc(a.expression)
+}
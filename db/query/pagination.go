@@ -0,0 +1,381 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Pagination décrit une pagination par curseur (keyset) : au lieu de sauter des lignes
+// avec OFFSET, chaque page reprend après (ou avant) la dernière ligne lue en comparant
+// les colonnes du tri à leurs valeurs, encodées dans un curseur opaque. La clé primaire
+// départage les lignes de même valeur de tri.
+type Pagination struct {
+	// Key liste les colonnes qualifiées de la clé primaire, ajoutées au tri si absentes
+	Key []string
+	// Sortable liste les colonnes qualifiées NOT NULL, seules acceptées dans le tri avec
+	// celles de Key : une valeur NULL ne peut pas être comparée à celle d'un curseur
+	Sortable []string
+	// After reprend après la ligne du curseur (page suivante)
+	After string
+	// Before reprend avant la ligne du curseur (page précédente)
+	Before string
+	// Size est le nombre maximal de lignes de la page
+	Size int
+}
+
+// keyset contient les requêtes d'une page préparées par apply
+type keyset struct {
+	// page lit les lignes de la page, plus une indiquant qu'une autre page suit
+	page *SelectQuery
+	// boundary vérifie qu'une ligne existe de l'autre côté du curseur, nil sans curseur
+	boundary *SelectQuery
+	// orderings contient la clé de tri complète, dans le sens demandé
+	orderings []Ordering
+}
+
+// Paginate exécute une copie de la requête paginée et retourne la page. fetch lit les
+// lignes de la requête paginée ; value retourne la valeur d'une colonne qualifiée du tri
+// pour une ligne, et false si la colonne ne fait pas partie de la ligne. La requête q
+// n'est pas modifiée et ne doit définir ni LIMIT ni OFFSET.
+func Paginate[T any](ctx context.Context, exec Executor, q *SelectQuery, p Pagination, fetch func(*SelectQuery) ([]T, error), value func(row T, column string) (interface{}, bool)) (*Page[T], error) {
+	k, err := p.apply(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := fetch(k.page)
+	if err != nil {
+		return nil, err
+	}
+
+	beyond := false
+	if k.boundary != nil {
+		if beyond, err = exists(ctx, exec, k.boundary); err != nil {
+			return nil, err
+		}
+	}
+
+	return newPage(p, k, rows, beyond, value)
+}
+
+// apply prépare sur des copies de q la requête de la page (tri complété par la clé
+// primaire, condition du curseur, LIMIT Size+1) et celle de la borne opposée. Avec
+// Before, le tri de la page est inversé : newPage remet les lignes dans l'ordre demandé.
+func (p Pagination) apply(q *SelectQuery) (*keyset, error) {
+	if p.Size <= 0 {
+		return nil, fmt.Errorf("la taille de page doit être positive: %d", p.Size)
+	}
+	if p.After != "" && p.Before != "" {
+		return nil, fmt.Errorf("After et Before ne peuvent pas être combinés")
+	}
+	if q.limit != nil || q.offset != nil {
+		return nil, fmt.Errorf("la pagination par curseur ne peut pas être combinée à Limit ou Offset")
+	}
+
+	page := q.Clone()
+	for _, column := range p.Key {
+		if !slices.ContainsFunc(page.orderBy, func(ordering Ordering) bool { return ordering.expression == column }) {
+			page.orderBy = append(page.orderBy, Asc(column))
+		}
+	}
+	if len(page.orderBy) == 0 {
+		return nil, fmt.Errorf("la pagination par curseur nécessite un tri ou une clé primaire")
+	}
+	for _, ordering := range page.orderBy {
+		if !slices.Contains(p.Key, ordering.expression) && !slices.Contains(p.Sortable, ordering.expression) {
+			return nil, fmt.Errorf("la colonne de tri %s doit être une colonne NOT NULL pour servir de curseur", ordering.expression)
+		}
+		if !page.selects(ordering.expression) {
+			return nil, fmt.Errorf("la colonne de tri %s doit être sélectionnée pour construire le curseur", ordering.expression)
+		}
+	}
+
+	k := &keyset{page: page, orderings: slices.Clone(page.orderBy)}
+
+	if cursor := p.After + p.Before; cursor != "" {
+		values, err := decodeCursor(cursor, k.orderings)
+		if err != nil {
+			return nil, err
+		}
+		before := p.Before != ""
+		page.Where(keysetCondition{orderings: k.orderings, values: values, before: before})
+
+		// La borne opposée contient la ligne du curseur et celles qui la suivent (Before)
+		// ou la précèdent (After) : elle existe si une page existe de ce côté
+		k.boundary = q.Clone()
+		k.boundary.columns = []string{"1"}
+		k.boundary.distinct, k.boundary.distinctOn, k.boundary.orderBy = false, nil, nil
+		k.boundary.Where(keysetCondition{orderings: k.orderings, values: values, before: !before, inclusive: true})
+		k.boundary.Limit(1)
+	}
+
+	if p.Before != "" {
+		for i, ordering := range page.orderBy {
+			page.orderBy[i] = ordering.reversed()
+		}
+	}
+	page.Limit(p.Size + 1)
+	return k, nil
+}
+
+// exists vérifie si la requête retourne au moins une ligne
+func exists(ctx context.Context, exec Executor, q *SelectQuery) (bool, error) {
+	rows, err := q.ExecuteContext(ctx, exec)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	found := rows.Next()
+	return found, rows.Err()
+}
+
+// Page est une page de résultats obtenue par pagination par curseur
+type Page[T any] struct {
+	Items []T
+
+	hasNext        bool
+	hasPrevious    bool
+	nextCursor     string
+	previousCursor string
+}
+
+// HasNext indique si d'autres lignes suivent la page
+func (p *Page[T]) HasNext() bool {
+	return p.hasNext
+}
+
+// NextCursor retourne le curseur de la page suivante, à passer à After (vide sans page suivante)
+func (p *Page[T]) NextCursor() string {
+	return p.nextCursor
+}
+
+// HasPrevious indique si d'autres lignes précèdent la page
+func (p *Page[T]) HasPrevious() bool {
+	return p.hasPrevious
+}
+
+// PreviousCursor retourne le curseur de la page précédente, à passer à Before (vide sans page précédente)
+func (p *Page[T]) PreviousCursor() string {
+	return p.previousCursor
+}
+
+// newPage construit la page à partir des lignes lues par la requête de apply (au plus
+// Size+1) ; beyond indique qu'une ligne existe de l'autre côté du curseur
+func newPage[T any](p Pagination, k *keyset, rows []T, beyond bool, value func(row T, column string) (interface{}, bool)) (*Page[T], error) {
+	more := len(rows) > p.Size
+	if more {
+		rows = rows[:p.Size]
+	}
+	if p.Before != "" {
+		slices.Reverse(rows)
+	}
+
+	page := &Page[T]{Items: rows}
+	if p.Before != "" {
+		page.hasNext, page.hasPrevious = beyond, more
+	} else {
+		page.hasNext, page.hasPrevious = more, beyond
+	}
+	if len(rows) == 0 {
+		// Aucune ligne à ce endroit : le curseur reçu permet de repartir dans l'autre sens
+		if page.hasNext {
+			page.nextCursor = p.Before
+		}
+		if page.hasPrevious {
+			page.previousCursor = p.After
+		}
+		return page, nil
+	}
+
+	var err error
+	if page.hasNext {
+		if page.nextCursor, err = rowCursor(k.orderings, rows[len(rows)-1], value); err != nil {
+			return nil, err
+		}
+	}
+	if page.hasPrevious {
+		if page.previousCursor, err = rowCursor(k.orderings, rows[0], value); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// rowCursor encode les valeurs de la clé de tri d'une ligne
+func rowCursor[T any](orderings []Ordering, row T, value func(row T, column string) (interface{}, bool)) (string, error) {
+	values := make([]interface{}, len(orderings))
+	for i, ordering := range orderings {
+		v, ok := value(row, ordering.expression)
+		if !ok {
+			return "", fmt.Errorf("le tri sur %s ne peut pas servir de curseur", ordering.expression)
+		}
+		if v == nil {
+			return "", fmt.Errorf("la colonne de tri %s vaut NULL et ne peut pas servir de curseur", ordering.expression)
+		}
+		values[i] = v
+	}
+	return encodeCursor(orderings, values)
+}
+
+// keysetCondition sélectionne les lignes situées après (ou avant) les valeurs du curseur
+// selon la clé de tri : (a > $1) OR (a = $1 AND b > $2)... en tenant compte du sens de
+// chaque critère. Avec inclusive, la ligne du curseur elle-même est sélectionnée.
+type keysetCondition struct {
+	orderings []Ordering
+	values    []interface{}
+	before    bool
+	inclusive bool
+}
+
+func (c keysetCondition) build(args *arguments) string {
+	alternatives := make([]string, len(c.orderings))
+	for i, ordering := range c.orderings {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = %s", c.orderings[j].expression, args.add(c.values[j])))
+		}
+		operator := ">"
+		if (ordering.direction == Descending) != c.before {
+			operator = "<"
+		}
+		if c.inclusive && i == len(c.orderings)-1 {
+			operator += "="
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", ordering.expression, operator, args.add(c.values[i])))
+		alternatives[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// cursorSecret est la clé HMAC qui signe les curseurs. Sans SetCursorSecret, elle est tirée
+// au hasard au démarrage : les curseurs ne restent valides que dans le processus qui les a produits.
+var cursorSecret atomic.Pointer[[]byte]
+
+func init() {
+	secret := make([]byte, sha256.Size)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("impossible de générer la clé des curseurs: %v", err))
+	}
+	cursorSecret.Store(&secret)
+}
+
+// SetCursorSecret remplace la clé qui signe et vérifie les curseurs de pagination. Une clé
+// partagée permet à plusieurs instances de l'application d'accepter les curseurs des autres
+// et aux curseurs de survivre à un redémarrage. Les curseurs signés avec l'ancienne clé
+// deviennent invalides.
+func SetCursorSecret(secret []byte) {
+	if len(secret) == 0 {
+		panic("la clé des curseurs ne peut pas être vide")
+	}
+	secret = bytes.Clone(secret)
+	cursorSecret.Store(&secret)
+}
+
+// cursorMAC calcule le HMAC-SHA256 du contenu d'un curseur
+func cursorMAC(payload []byte) []byte {
+	mac := hmac.New(sha256.New, *cursorSecret.Load())
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// cursorData est le contenu d'un curseur : l'identifiant du tri qui l'a produit et les
+// valeurs de la clé de tri
+type cursorData struct {
+	Order  string        `json:"o"`
+	Values []interface{} `json:"v"`
+}
+
+// orderSignature identifie une clé de tri ("posts.published_at DESC,posts.id ASC")
+func orderSignature(orderings []Ordering) string {
+	parts := make([]string, len(orderings))
+	for i, ordering := range orderings {
+		parts[i] = ordering.build()
+	}
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor encode des valeurs en curseur opaque : le JSON et son HMAC (voir
+// SetCursorSecret), en base64 URL séparés par un point
+func encodeCursor(orderings []Ordering, values []interface{}) (string, error) {
+	data, err := json.Marshal(cursorData{Order: orderSignature(orderings), Values: values})
+	if err != nil {
+		return "", fmt.Errorf("impossible d'encoder le curseur: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(cursorMAC(data)), nil
+}
+
+// decodeCursor vérifie le HMAC d'un curseur, le décode et vérifie qu'il a été produit par la
+// même clé de tri. Un curseur modifié par le client est refusé avant d'être décodé.
+// Les nombres sont conservés sous forme textuelle (json.Number) pour ne pas perdre de précision.
+func decodeCursor(cursor string, orderings []Ordering) ([]interface{}, error) {
+	encodedData, encodedMAC, found := strings.Cut(cursor, ".")
+	if !found {
+		return nil, fmt.Errorf("curseur invalide: signature absente")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encodedData)
+	if err != nil {
+		return nil, fmt.Errorf("curseur invalide: %w", err)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil {
+		return nil, fmt.Errorf("curseur invalide: %w", err)
+	}
+	if !hmac.Equal(mac, cursorMAC(data)) {
+		return nil, fmt.Errorf("curseur invalide: signature incorrecte")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var content cursorData
+	if err := decoder.Decode(&content); err != nil {
+		return nil, fmt.Errorf("curseur invalide: %w", err)
+	}
+	if content.Order != orderSignature(orderings) {
+		return nil, fmt.Errorf("curseur invalide: il a été produit par un autre tri (%s)", content.Order)
+	}
+	if len(content.Values) != len(orderings) {
+		return nil, fmt.Errorf("curseur invalide: %d valeur(s) pour %d critère(s) de tri", len(content.Values), len(orderings))
+	}
+	if slices.Contains(content.Values, nil) {
+		return nil, fmt.Errorf("curseur invalide: valeur NULL")
+	}
+	return content.Values, nil
+}
+
+// reversed retourne le critère de tri inverse, NULLS FIRST et NULLS LAST compris
+func (o Ordering) reversed() Ordering {
+	if o.direction == Ascending {
+		o.direction = Descending
+	} else {
+		o.direction = Ascending
+	}
+	switch o.nulls {
+	case NullsFirst:
+		o.nulls = NullsLast
+	case NullsLast:
+		o.nulls = NullsFirst
+	}
+	return o
+}
+
+// selects vérifie si la colonne qualifiée fait partie de la liste SELECT
+func (q *SelectQuery) selects(expression string) bool {
+	table := q.table
+	if q.alias != "" {
+		table = q.alias
+	}
+	return slices.ContainsFunc(q.columns, func(column string) bool {
+		return column == "*" || column == expression || table+"."+column == expression
+	})
+}
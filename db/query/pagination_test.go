@@ -0,0 +1,318 @@
+package query
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// postRow est une ligne de test triée par date puis par id
+type postRow struct {
+	ID   int
+	Date string
+}
+
+func postValue(row postRow, column string) (interface{}, bool) {
+	switch column {
+	case "posts.id":
+		return row.ID, true
+	case "posts.date":
+		return row.Date, true
+	}
+	return nil, false
+}
+
+// newPostsPagination retourne une pagination sur posts triée par date décroissante
+func newPostsPagination(after, before string) (*SelectQuery, Pagination) {
+	q := NewSelectQuery("posts").AddColumn("id").AddColumn("date").OrderBy(Desc("posts.date"))
+	p := Pagination{
+		Key:      []string{"posts.id"},
+		Sortable: []string{"posts.date"},
+		After:    after,
+		Before:   before,
+		Size:     2,
+	}
+	return q, p
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	orderings := []Ordering{Desc("posts.date"), Asc("posts.id")}
+
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   []interface{}
+	}{
+		{"texte et entier", []interface{}{"2024-01-02", 42}, []interface{}{"2024-01-02", "42"}},
+		{"grand entier", []interface{}{"d", int64(9007199254740993)}, []interface{}{"d", "9007199254740993"}},
+		{"booléen", []interface{}{true, 1}, []interface{}{true, "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := encodeCursor(orderings, tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values, err := decodeCursor(cursor, orderings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Les nombres sont décodés en json.Number, comparés sous forme textuelle
+			got := make([]interface{}, len(values))
+			for i, value := range values {
+				if _, ok := value.(bool); ok {
+					got[i] = value
+				} else {
+					got[i] = toText(value)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("valeurs décodées = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	orderings := []Ordering{Desc("posts.date"), Asc("posts.id")}
+	valid, _ := encodeCursor(orderings, []interface{}{"a", 1})
+	otherOrder, _ := encodeCursor([]Ordering{Asc("posts.date"), Asc("posts.id")}, []interface{}{"a", 1})
+	tooShort, _ := encodeCursor(orderings, []interface{}{"a"})
+	withNull, _ := encodeCursor(orderings, []interface{}{nil, 1})
+	// Un curseur dont le contenu est modifié garde la signature du contenu d'origine
+	payload, mac, _ := strings.Cut(valid, ".")
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"o":"posts.date DESC,posts.id ASC","v":["a",2]}`)) + "." + mac
+	notJSON := base64.RawURLEncoding.EncodeToString([]byte("nope"))
+	notJSON += "." + base64.RawURLEncoding.EncodeToString(cursorMAC([]byte("nope")))
+
+	tests := []struct {
+		name    string
+		cursor  string
+		wantErr string
+	}{
+		{"valide", valid, ""},
+		{"sans signature", payload, "signature absente"},
+		{"pas du base64", "%%%." + mac, "curseur invalide"},
+		{"signature modifiée", payload + "." + mac[1:], "curseur invalide"},
+		{"contenu modifié", tampered, "signature incorrecte"},
+		{"pas du JSON", notJSON, "curseur invalide"},
+		{"autre tri", otherOrder, "autre tri"},
+		{"nombre de valeurs", tooShort, "1 valeur(s) pour 2"},
+		{"valeur NULL", withNull, "NULL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(tt.cursor, orderings)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("erreur inattendue: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPaginationApply(t *testing.T) {
+	cursor, _ := encodeCursor([]Ordering{Desc("posts.date"), Asc("posts.id")}, []interface{}{"b", 2})
+
+	tests := []struct {
+		name         string
+		after        string
+		before       string
+		wantPage     string
+		wantBoundary string
+		wantValues   []interface{}
+	}{
+		{
+			name:     "première page",
+			wantPage: "SELECT id, date FROM posts ORDER BY posts.date DESC, posts.id ASC LIMIT 3",
+		},
+		{
+			name:         "après le curseur",
+			after:        cursor,
+			wantPage:     "SELECT id, date FROM posts WHERE ((posts.date < $1) OR (posts.date = $2 AND posts.id > $3)) ORDER BY posts.date DESC, posts.id ASC LIMIT 3",
+			wantBoundary: "SELECT 1 FROM posts WHERE ((posts.date > $1) OR (posts.date = $2 AND posts.id <= $3)) LIMIT 1",
+			wantValues:   []interface{}{"b", "b", "2"},
+		},
+		{
+			name:         "avant le curseur, tri inversé",
+			before:       cursor,
+			wantPage:     "SELECT id, date FROM posts WHERE ((posts.date > $1) OR (posts.date = $2 AND posts.id < $3)) ORDER BY posts.date ASC, posts.id DESC LIMIT 3",
+			wantBoundary: "SELECT 1 FROM posts WHERE ((posts.date < $1) OR (posts.date = $2 AND posts.id >= $3)) LIMIT 1",
+			wantValues:   []interface{}{"b", "b", "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, p := newPostsPagination(tt.after, tt.before)
+			original := q.Build()
+
+			k, err := p.apply(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := k.page.Build(); got != tt.wantPage {
+				t.Errorf("page =\n%s\nattendu\n%s", got, tt.wantPage)
+			}
+			if tt.wantBoundary == "" {
+				if k.boundary != nil {
+					t.Errorf("borne inattendue: %s", k.boundary.Build())
+				}
+			} else {
+				if got := k.boundary.Build(); got != tt.wantBoundary {
+					t.Errorf("borne =\n%s\nattendu\n%s", got, tt.wantBoundary)
+				}
+				values := make([]interface{}, 0, len(tt.wantValues))
+				for _, value := range k.page.GetValues() {
+					values = append(values, toText(value))
+				}
+				if !reflect.DeepEqual(values, tt.wantValues) {
+					t.Errorf("valeurs = %v, attendu %v", values, tt.wantValues)
+				}
+			}
+
+			// La requête de l'appelant n'est pas modifiée : une seconde page est identique
+			if got := q.Build(); got != original {
+				t.Errorf("requête modifiée: %s", got)
+			}
+			k2, err := p.apply(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if k2.page.Build() != k.page.Build() {
+				t.Errorf("seconde application différente: %s", k2.page.Build())
+			}
+		})
+	}
+}
+
+func TestPaginationApplyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(q *SelectQuery, p *Pagination)
+		wantErr string
+	}{
+		{"taille nulle", func(q *SelectQuery, p *Pagination) { p.Size = 0 }, "taille de page"},
+		{"After et Before", func(q *SelectQuery, p *Pagination) { p.After, p.Before = "a", "b" }, "combinés"},
+		{"limite de l'appelant", func(q *SelectQuery, p *Pagination) { q.Limit(10) }, "Limit ou Offset"},
+		{"décalage de l'appelant", func(q *SelectQuery, p *Pagination) { q.Offset(10) }, "Limit ou Offset"},
+		{"colonne nullable", func(q *SelectQuery, p *Pagination) { q.OrderBy(Asc("posts.published_at")) }, "NOT NULL"},
+		{"colonne non sélectionnée", func(q *SelectQuery, p *Pagination) {
+			p.Sortable = append(p.Sortable, "posts.title")
+			q.OrderBy(Asc("posts.title"))
+		}, "sélectionnée"},
+		{"sans tri ni clé", func(q *SelectQuery, p *Pagination) { q.orderBy = nil; p.Key = nil }, "nécessite un tri"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, p := newPostsPagination("", "")
+			tt.prepare(q, &p)
+			_, err := p.apply(q)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPageBoundaries(t *testing.T) {
+	cursor, _ := encodeCursor([]Ordering{Desc("posts.date"), Asc("posts.id")}, []interface{}{"c", 3})
+
+	tests := []struct {
+		name         string
+		after        string
+		before       string
+		rows         []postRow
+		beyond       bool
+		wantItems    []postRow
+		wantNext     bool
+		wantPrevious bool
+	}{
+		{"première page, suite", "", "", []postRow{{5, "e"}, {4, "d"}, {3, "c"}}, false, []postRow{{5, "e"}, {4, "d"}}, true, false},
+		{"première page complète, fin", "", "", []postRow{{5, "e"}, {4, "d"}}, false, []postRow{{5, "e"}, {4, "d"}}, false, false},
+		{"après, dernière page", cursor, "", []postRow{{2, "b"}}, true, []postRow{{2, "b"}}, false, true},
+		{"après, début supprimé", cursor, "", []postRow{{2, "b"}}, false, []postRow{{2, "b"}}, false, false},
+		{"avant, lignes inversées", "", cursor, []postRow{{4, "d"}, {5, "e"}, {6, "f"}}, true, []postRow{{5, "e"}, {4, "d"}}, true, true},
+		{"avant, curseur en fin de table", "", cursor, []postRow{{4, "d"}}, false, []postRow{{4, "d"}}, false, false},
+		{"avant, page vide", "", cursor, nil, true, nil, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, p := newPostsPagination(tt.after, tt.before)
+			k, err := p.apply(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			page, err := newPage(p, k, tt.rows, tt.beyond, postValue)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(page.Items, tt.wantItems) {
+				t.Errorf("Items = %v, attendu %v", page.Items, tt.wantItems)
+			}
+			if page.HasNext() != tt.wantNext || page.HasPrevious() != tt.wantPrevious {
+				t.Errorf("HasNext, HasPrevious = %v, %v, attendu %v, %v", page.HasNext(), page.HasPrevious(), tt.wantNext, tt.wantPrevious)
+			}
+			if (page.NextCursor() != "") != tt.wantNext || (page.PreviousCursor() != "") != tt.wantPrevious {
+				t.Errorf("curseurs incohérents: next=%q previous=%q", page.NextCursor(), page.PreviousCursor())
+			}
+
+			// Le curseur suivant reprend après la dernière ligne de la page
+			if len(page.Items) > 0 && page.HasNext() {
+				values, err := decodeCursor(page.NextCursor(), k.orderings)
+				if err != nil {
+					t.Fatal(err)
+				}
+				last := page.Items[len(page.Items)-1]
+				if toText(values[0]) != last.Date || toText(values[1]) != toText(last.ID) {
+					t.Errorf("NextCursor = %v, attendu la ligne %v", values, last)
+				}
+			}
+		})
+	}
+}
+
+func TestRowCursorRejectsNull(t *testing.T) {
+	value := func(row postRow, column string) (interface{}, bool) { return nil, true }
+	if _, err := rowCursor([]Ordering{Asc("posts.id")}, postRow{}, value); err == nil {
+		t.Fatal("une valeur NULL doit être refusée")
+	}
+	unknown := func(row postRow, column string) (interface{}, bool) { return nil, false }
+	if _, err := rowCursor([]Ordering{Asc("posts.id")}, postRow{}, unknown); err == nil {
+		t.Fatal("une colonne inconnue doit être refusée")
+	}
+}
+
+// toText retourne la représentation textuelle d'une valeur de curseur (json.Number ou valeur Go)
+func toText(value interface{}) string {
+	return fmt.Sprint(value)
+}
+
+func TestSetCursorSecret(t *testing.T) {
+	previous := *cursorSecret.Load()
+	defer SetCursorSecret(previous)
+
+	orderings := []Ordering{Asc("posts.id")}
+	SetCursorSecret([]byte("clé partagée"))
+	cursor, err := encodeCursor(orderings, []interface{}{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Une autre instance configurée avec la même clé accepte le curseur
+	SetCursorSecret([]byte("clé partagée"))
+	if _, err := decodeCursor(cursor, orderings); err != nil {
+		t.Fatalf("erreur inattendue: %v", err)
+	}
+
+	SetCursorSecret([]byte("autre clé"))
+	if _, err := decodeCursor(cursor, orderings); err == nil || !strings.Contains(err.Error(), "signature incorrecte") {
+		t.Fatalf("erreur = %v, attendu une signature incorrecte", err)
+	}
+}
@@ -0,0 +1,29 @@
+package query
+
+import (
+	"strings"
+)
+
+// returningClause contient les colonnes retournées par une requête de modification
+// (INSERT, UPDATE ou DELETE ... RETURNING)
+type returningClause struct {
+	returning []string
+}
+
+// setReturning remplace les colonnes retournées par la requête
+func (r *returningClause) setReturning(columns []string) {
+	r.returning = columns
+}
+
+// buildReturning construit la clause RETURNING, vide si aucune colonne n'est demandée
+func (r *returningClause) buildReturning() string {
+	if len(r.returning) == 0 {
+		return ""
+	}
+	return "RETURNING " + strings.Join(r.returning, ", ")
+}
+
+// GetReturning retourne les colonnes de la clause RETURNING
+func (r *returningClause) GetReturning() []string {
+	return r.returning
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReturning(t *testing.T) {
+	tests := []struct {
+		name       string
+		build      func() (string, []interface{})
+		want       string
+		wantValues []interface{}
+	}{
+		{
+			name:       "insertion",
+			build:      NewInsertQuery("posts").AddColumn("title").AddValue("Bonjour").Returning("id", "created_at").build,
+			want:       "INSERT INTO posts (title) VALUES ($1) RETURNING id, created_at",
+			wantValues: []interface{}{"Bonjour"},
+		},
+		{
+			name:       "mise à jour",
+			build:      NewUpdateQuery("posts").AddColumn("title").AddValue("Salut").Where(Eq("id", 7)).Returning("*").build,
+			want:       "UPDATE posts SET title = $1 WHERE id = $2 RETURNING *",
+			wantValues: []interface{}{"Salut", 7},
+		},
+		{
+			name:       "suppression",
+			build:      NewDeleteQuery("posts").Where(Eq("id", 7)).Returning("id").build,
+			want:       "DELETE FROM posts WHERE id = $1 RETURNING id",
+			wantValues: []interface{}{7},
+		},
+		{
+			name:       "sans RETURNING",
+			build:      NewDeleteQuery("posts").Where(Eq("id", 7)).build,
+			want:       "DELETE FROM posts WHERE id = $1",
+			wantValues: []interface{}{7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, values := tt.build()
+			if query != tt.want {
+				t.Errorf("requête = %s, attendu %s", query, tt.want)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("valeurs = %v, attendu %v", values, tt.wantValues)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+// Scan exécute la requête et lit chaque ligne dans un struct T fourni par l'appelant.
+// Chaque colonne du résultat est associée au champ exporté dont le tag db porte son nom
+// (le nom de la colonne ou l'alias d'un agrégat), y compris dans les structs embarqués :
+//
+//	type AuthorStats struct {
+//		AuthorId int   `db:"author_id"`
+//		Total    int64 `db:"total"`
+//	}
+func Scan[T any](ctx context.Context, exec Executor, q *SelectQuery) ([]T, error) {
+	rows, err := q.ExecuteContext(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	// Les champs des colonnes sont résolus une fois pour toutes les lignes
+	paths, err := structFields(reflect.TypeFor[T](), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []T
+	for rows.Next() {
+		var result T
+		value := reflect.ValueOf(&result).Elem()
+		targets := make([]interface{}, len(paths))
+		for i, path := range paths {
+			targets[i] = scanDestination(value.FieldByIndex(path))
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// ScanValue exécute la requête et lit la première colonne de la première ligne dans
+// une valeur de type T (ex: ScanValue[int64] pour un COUNT(*)). Une valeur NULL doit être
+// lue dans un pointeur ; sql.ErrNoRows est retourné si la requête ne produit aucune ligne.
+func ScanValue[T any](ctx context.Context, exec Executor, q *SelectQuery) (T, error) {
+	var value T
+	query, values := q.build()
+	err := exec.QueryRowContext(ctx, query, values...).Scan(scanDestination(reflect.ValueOf(&value).Elem()))
+	return value, err
+}
+
+// structFields retourne, pour chaque colonne, le chemin (reflect.Value.FieldByIndex) du
+// champ de T qui la reçoit. Un tag db sur un champ non exporté est une erreur, Scan ne
+// pouvant pas y écrire.
+func structFields(structType reflect.Type, columns []string) ([][]int, error) {
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Scan attend un struct, %s reçu", structType)
+	}
+
+	fields := make(map[string][]int)
+	if err := collectFields(structType, nil, fields); err != nil {
+		return nil, err
+	}
+
+	paths := make([][]int, len(columns))
+	for i, column := range columns {
+		path, ok := fields[column]
+		if !ok {
+			return nil, fmt.Errorf("aucun champ de %s n'a le tag db:\"%s\"", structType, column)
+		}
+		paths[i] = path
+	}
+	return paths, nil
+}
+
+// collectFields associe les tags db des champs du struct à leur chemin. Les champs du
+// struct sont prioritaires sur ceux des structs embarqués, parcourus ensuite.
+func collectFields(structType reflect.Type, prefix []int, fields map[string][]int) error {
+	var embedded []reflect.StructField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		field.Index = append(slices.Clone(prefix), i)
+
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				embedded = append(embedded, field)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("le champ %s.%s (db:\"%s\") n'est pas exporté", structType, field.Name, tag)
+		}
+		if _, ok := fields[tag]; !ok {
+			fields[tag] = field.Index
+		}
+	}
+
+	for _, field := range embedded {
+		if err := collectFields(field.Type, field.Index, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanDestination retourne la destination de Scan d'une valeur adressable, les
+// tableaux (hors []byte) étant lus par pq.Array
+func scanDestination(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8 {
+		return pq.Array(value.Addr().Interface())
+	}
+	return value.Addr().Interface()
+}
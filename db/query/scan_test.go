@@ -0,0 +1,85 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type auditFields struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type Timestamps struct {
+	CreatedAt string `db:"created_at"`
+	Total     int64  `db:"total"` // masqué par le champ Total du struct englobant
+}
+
+type authorStats struct {
+	AuthorId *int     `db:"author_id"`
+	Total    int64    `db:"total"`
+	Titles   []string `db:"titles"`
+	Ignored  string   `db:"-"`
+	Timestamps
+	auditFields
+}
+
+type hiddenField struct {
+	total int64 `db:"total"`
+}
+
+func TestStructFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		columns []string
+		want    [][]int
+		wantErr string
+	}{
+		{"colonnes dans le désordre", authorStats{}, []string{"total", "author_id"}, [][]int{{1}, {0}}, ""},
+		{"struct embarqué", authorStats{}, []string{"created_at"}, [][]int{{4, 0}}, ""},
+		{"struct embarqué non exporté", authorStats{}, []string{"created_by"}, [][]int{{5, 0}}, ""},
+		{"champ englobant prioritaire", authorStats{}, []string{"total"}, [][]int{{1}}, ""},
+		{"colonne sans champ", authorStats{}, []string{"count"}, nil, `tag db:"count"`},
+		{"tag ignoré", authorStats{}, []string{"-"}, nil, `tag db:"-"`},
+		{"champ non exporté", hiddenField{}, []string{"total"}, nil, "n'est pas exporté"},
+		{"pas un struct", int64(0), []string{"count"}, nil, "attend un struct"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := structFields(reflect.TypeOf(tt.value), tt.columns)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chemins = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanDestination(t *testing.T) {
+	var row authorStats
+	value := reflect.ValueOf(&row).Elem()
+	paths, err := structFields(value.Type(), []string{"author_id", "titles", "created_by"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Les champs, même promus depuis un struct embarqué non exporté, sont adressables
+	if _, ok := scanDestination(value.FieldByIndex(paths[0])).(**int); !ok {
+		t.Errorf("author_id doit être lu dans un **int")
+	}
+	if got := reflect.TypeOf(scanDestination(value.FieldByIndex(paths[1]))).String(); got != "*pq.StringArray" {
+		t.Errorf("titles doit être lu par pq.Array, destination %s", got)
+	}
+	if _, ok := scanDestination(value.FieldByIndex(paths[2])).(*string); !ok {
+		t.Errorf("created_by doit être lu dans un *string")
+	}
+}
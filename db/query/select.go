@@ -1,15 +1,26 @@
 package query
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"slices"
 	"strings"
 )
 
 type SelectQuery struct {
 	table      string
+	alias      string
 	columns    []string
-	conditions []string
-	values     []interface{}
+	joins      []join
+	conditions []Condition
+	groupBy    []string
+	having     []Condition
+	orderBy    []Ordering
+	limit      *int
+	offset     *int
+	distinct   bool
+	distinctOn []string
 }
 
 func NewSelectQuery(table string) *SelectQuery {
@@ -18,50 +29,215 @@ func NewSelectQuery(table string) *SelectQuery {
 	}
 }
 
+// As définit l'alias de la table principale (FROM posts AS p). Les colonnes et les
+// conditions doivent alors être qualifiées par l'alias.
+func (q *SelectQuery) As(alias string) *SelectQuery {
+	q.alias = alias
+	return q
+}
+
+// Join ajoute une jointure du type donné sur la table, avec sa condition ON
+func (q *SelectQuery) Join(kind JoinKind, table string, on Condition) *SelectQuery {
+	return q.JoinAs(kind, table, "", on)
+}
+
+// JoinAs ajoute une jointure sur la table désignée par un alias, nécessaire pour
+// joindre deux fois la même table ou une table à elle-même
+// (ex: JoinAs(JoinLeft, "users", "reviewer", EqColumns("reviewer.id", "posts.reviewer_id")))
+func (q *SelectQuery) JoinAs(kind JoinKind, table, alias string, on Condition) *SelectQuery {
+	q.joins = append(q.joins, join{kind: kind, table: table, alias: alias, on: on})
+	return q
+}
+
+// InnerJoin ajoute une jointure interne : seules les lignes ayant une correspondance sont retournées
+func (q *SelectQuery) InnerJoin(table string, on Condition) *SelectQuery {
+	return q.Join(JoinInner, table, on)
+}
+
+// LeftJoin ajoute une jointure externe gauche : les colonnes de la table jointe valent
+// NULL pour les lignes sans correspondance
+func (q *SelectQuery) LeftJoin(table string, on Condition) *SelectQuery {
+	return q.Join(JoinLeft, table, on)
+}
+
+// RightJoin ajoute une jointure externe droite
+func (q *SelectQuery) RightJoin(table string, on Condition) *SelectQuery {
+	return q.Join(JoinRight, table, on)
+}
+
+// FullJoin ajoute une jointure externe complète
+func (q *SelectQuery) FullJoin(table string, on Condition) *SelectQuery {
+	return q.Join(JoinFull, table, on)
+}
+
+// Clone retourne une copie indépendante de la requête, qui peut être modifiée
+// (Limit, Where...) sans effet sur l'originale
+func (q *SelectQuery) Clone() *SelectQuery {
+	clone := *q
+	clone.columns = slices.Clone(q.columns)
+	clone.joins = slices.Clone(q.joins)
+	clone.conditions = slices.Clone(q.conditions)
+	clone.groupBy = slices.Clone(q.groupBy)
+	clone.having = slices.Clone(q.having)
+	clone.orderBy = slices.Clone(q.orderBy)
+	clone.distinctOn = slices.Clone(q.distinctOn)
+	if q.limit != nil {
+		limit := *q.limit
+		clone.limit = &limit
+	}
+	if q.offset != nil {
+		offset := *q.offset
+		clone.offset = &offset
+	}
+	return &clone
+}
+
 func (q *SelectQuery) AddColumn(column string) *SelectQuery {
 	q.columns = append(q.columns, column)
 	return q
 }
 
-func (q *SelectQuery) AddCondition(condition string) *SelectQuery {
+// Select ajoute des colonnes typées, des agrégats ou des expressions à la liste SELECT
+// (ex: Select(generated.Posts.AuthorId, query.Count().As("total")))
+func (q *SelectQuery) Select(selections ...Selection) *SelectQuery {
+	for _, selection := range selections {
+		q.columns = append(q.columns, selection.Expression())
+	}
+	return q
+}
+
+// GroupBy regroupe les lignes selon les colonnes ou expressions données
+func (q *SelectQuery) GroupBy(selections ...Selection) *SelectQuery {
+	for _, selection := range selections {
+		q.groupBy = append(q.groupBy, selection.Expression())
+	}
+	return q
+}
+
+// Having ajoute une condition sur les groupes, combinée aux précédentes avec AND
+// (ex: Having(query.Count().Gt(10))). Ses valeurs sont liées comme celles du WHERE.
+func (q *SelectQuery) Having(condition Condition) *SelectQuery {
+	q.having = append(q.having, condition)
+	return q
+}
+
+// OrderBy ajoute des critères de tri, appliqués dans l'ordre
+// (ex: OrderBy(generated.Posts.PublishedAt.Desc().NullsLast(), generated.Posts.Id.Asc()))
+func (q *SelectQuery) OrderBy(orderings ...Ordering) *SelectQuery {
+	q.orderBy = append(q.orderBy, orderings...)
+	return q
+}
+
+// Limit définit le nombre maximal de lignes retournées
+func (q *SelectQuery) Limit(limit int) *SelectQuery {
+	q.limit = &limit
+	return q
+}
+
+// Offset définit le nombre de lignes ignorées avant la première ligne retournée
+func (q *SelectQuery) Offset(offset int) *SelectQuery {
+	q.offset = &offset
+	return q
+}
+
+// Distinct élimine les lignes en double du résultat (SELECT DISTINCT)
+func (q *SelectQuery) Distinct() *SelectQuery {
+	q.distinct = true
+	return q
+}
+
+// DistinctOn ne garde que la première ligne de chaque groupe de valeurs des expressions
+// données (SELECT DISTINCT ON). PostgreSQL exige que le tri commence par ces expressions.
+func (q *SelectQuery) DistinctOn(selections ...Selection) *SelectQuery {
+	for _, selection := range selections {
+		q.distinctOn = append(q.distinctOn, selection.Expression())
+	}
+	return q
+}
+
+func (q *SelectQuery) AddCondition(condition Condition) *SelectQuery {
 	q.conditions = append(q.conditions, condition)
 	return q
 }
 
-func (q *SelectQuery) Where(condition string) *SelectQuery {
+// Where ajoute une condition WHERE à la requête, combinée aux précédentes avec AND
+func (q *SelectQuery) Where(condition Condition) *SelectQuery {
 	q.conditions = append(q.conditions, condition)
 	return q
 }
 
 func (q *SelectQuery) Build() string {
-	query := "SELECT " + strings.Join(q.columns, ", ") + " FROM " + q.table
+	query, _ := q.build()
+	return query
+}
+
+// build construit la requête SQL et la liste des valeurs liées
+func (q *SelectQuery) build() (string, []interface{}) {
+	args := &arguments{}
+	query := "SELECT "
+	if len(q.distinctOn) > 0 {
+		query += "DISTINCT ON (" + strings.Join(q.distinctOn, ", ") + ") "
+	} else if q.distinct {
+		query += "DISTINCT "
+	}
+	query += strings.Join(q.columns, ", ") + " FROM " + q.table
+	if q.alias != "" {
+		query += " AS " + q.alias
+	}
+
+	for _, join := range q.joins {
+		query += " " + join.build(args)
+	}
 	
 	// Gestion des clauses WHERE
-	if len(q.conditions) > 0 {
-		whereClause := "WHERE " + strings.Join(q.conditions, " AND ")
-		query += " " + whereClause
+	if where := buildWhere(q.conditions, args); where != "" {
+		query += " " + where
+	}
+
+	if len(q.groupBy) > 0 {
+		query += " GROUP BY " + strings.Join(q.groupBy, ", ")
+	}
+
+	if having := buildClause("HAVING", q.having, args); having != "" {
+		query += " " + having
+	}
+
+	if len(q.orderBy) > 0 {
+		orderings := make([]string, len(q.orderBy))
+		for i, ordering := range q.orderBy {
+			orderings[i] = ordering.build()
+		}
+		query += " ORDER BY " + strings.Join(orderings, ", ")
+	}
+
+	if q.limit != nil {
+		query += fmt.Sprintf(" LIMIT %d", *q.limit)
+	}
+
+	if q.offset != nil {
+		query += fmt.Sprintf(" OFFSET %d", *q.offset)
 	}
 	
-	return query
+	return query, args.values
+}
+
+func (q *SelectQuery) Execute(exec Executor) (*sql.Rows, error) {
+	return q.ExecuteContext(context.Background(), exec)
 }
 
-func (q *SelectQuery) Execute(db *sql.DB) (*sql.Rows, error) {
-	query := q.Build()
-	rows, err := db.Query(query, q.values...)
+// ExecuteContext exécute la requête en respectant l'annulation et l'échéance du contexte
+func (q *SelectQuery) ExecuteContext(ctx context.Context, exec Executor) (*sql.Rows, error) {
+	query, values := q.build()
+	rows, err := exec.QueryContext(ctx, query, values...)
 	if err != nil {
 		return nil, err
 	}
 	return rows, nil
 }
 
-// WhereWithValue ajoute une condition WHERE avec une valeur paramétrée
-func (q *SelectQuery) WhereWithValue(condition string, value interface{}) *SelectQuery {
-	q.conditions = append(q.conditions, condition)
-	q.values = append(q.values, value)
-	return q
-}
-
-// GetValues retourne les valeurs de la requête (utile pour le générateur)
+// GetValues retourne les valeurs liées de la requête, dans l'ordre des placeholders
+// (utile pour le générateur)
 func (q *SelectQuery) GetValues() []interface{} {
-	return q.values
+	_, values := q.build()
+	return values
 }
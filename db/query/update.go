@@ -1,13 +1,16 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 )
 
 type UpdateQuery struct {
 	BaseQuery
+	returningClause
 	table   string
 	columns []string
 	values  []interface{}
@@ -24,12 +27,22 @@ func (q *UpdateQuery) AddColumn(column string) *UpdateQuery {
 	return q
 }
 
+// RemoveColumn retire une colonne et sa valeur de la requête
+func (q *UpdateQuery) RemoveColumn(column string) *UpdateQuery {
+	if i := slices.Index(q.columns, column); i >= 0 {
+		q.columns = slices.Delete(q.columns, i, i+1)
+		q.values = slices.Delete(q.values, i, i+1)
+	}
+	return q
+}
+
 func (q *UpdateQuery) AddValue(value interface{}) *UpdateQuery {
 	q.values = append(q.values, value)
 	return q
 }
 
-func (q *UpdateQuery) Where(condition string) *UpdateQuery {
+// Where ajoute une condition WHERE à la requête, combinée aux précédentes avec AND
+func (q *UpdateQuery) Where(condition Condition) *UpdateQuery {
 	q.conditions = append(q.conditions, condition)
 	return q
 }
@@ -50,32 +63,78 @@ func (q *UpdateQuery) Offset(offset int) *UpdateQuery {
 }
 
 func (q *UpdateQuery) Build() string {
+	query, _ := q.build()
+	return query
+}
+
+// build construit la requête SQL et la liste des valeurs liées.
+// Les valeurs du SET occupent les premiers placeholders, les conditions
+// WHERE sont numérotées à leur suite.
+func (q *UpdateQuery) build() (string, []interface{}) {
 	if len(q.columns) != len(q.values) {
 		panic("Number of columns and values must match")
 	}
 
+	args := &arguments{}
 	var setPairs []string
 	for i, column := range q.columns {
-		setPairs = append(setPairs, fmt.Sprintf("%s = $%d", column, i+1))
+		setPairs = append(setPairs, fmt.Sprintf("%s = %s", column, args.add(q.values[i])))
 	}
 
 	query := fmt.Sprintf("UPDATE %s SET %s", q.table, strings.Join(setPairs, ", "))
 
-	commonClauses := q.buildCommonClauses()
+	commonClauses := q.buildCommonClauses(args)
 	if commonClauses != "" {
 		query += " " + commonClauses
 	}
 
-	return query
+	if returning := q.buildReturning(); returning != "" {
+		query += " " + returning
+	}
+
+	return query, args.values
+}
+
+// Returning définit les colonnes retournées pour chaque ligne mise à jour (RETURNING).
+// Un nouvel appel remplace les colonnes précédentes.
+func (q *UpdateQuery) Returning(columns ...string) *UpdateQuery {
+	q.setReturning(columns)
+	return q
+}
+
+func (q *UpdateQuery) Execute(exec Executor) error {
+	return q.ExecuteContext(context.Background(), exec)
 }
 
-func (q *UpdateQuery) Execute(db *sql.DB) error {
-	query := q.Build()
-	_, err := db.Exec(query, q.values...)
+// ExecuteContext exécute la requête en respectant l'annulation et l'échéance du contexte
+func (q *UpdateQuery) ExecuteContext(ctx context.Context, exec Executor) error {
+	query, values := q.build()
+	_, err := exec.ExecContext(ctx, query, values...)
 	return err
 }
 
-// GetValues retourne les valeurs de la requête (utile pour le générateur)
+// ExecuteReturning exécute la mise à jour et retourne les lignes de la clause RETURNING
+func (q *UpdateQuery) ExecuteReturning(exec Executor) (*sql.Rows, error) {
+	return q.ExecuteReturningContext(context.Background(), exec)
+}
+
+// ExecuteReturningContext exécute la mise à jour comme ExecuteReturning en respectant le contexte
+func (q *UpdateQuery) ExecuteReturningContext(ctx context.Context, exec Executor) (*sql.Rows, error) {
+	if len(q.returning) == 0 {
+		return nil, fmt.Errorf("aucune colonne RETURNING définie pour la mise à jour de %s", q.table)
+	}
+	query, values := q.build()
+	return exec.QueryContext(ctx, query, values...)
+}
+
+// GetValues retourne les valeurs liées de la requête (SET puis WHERE),
+// dans l'ordre des placeholders (utile pour le générateur)
 func (q *UpdateQuery) GetValues() []interface{} {
-	return q.values
+	_, values := q.build()
+	return values
+}
+
+// GetColumns retourne les colonnes mises à jour par la requête
+func (q *UpdateQuery) GetColumns() []string {
+	return q.columns
 }
@@ -0,0 +1,93 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// conflictUpdate représente une affectation de la clause ON CONFLICT ... DO UPDATE SET
+type conflictUpdate struct {
+	column string
+	value  interface{}
+	// excluded indique que la colonne reçoit la valeur proposée à l'insertion (EXCLUDED.column)
+	excluded bool
+}
+
+// conflictClause contient la clause ON CONFLICT d'une insertion (upsert)
+type conflictClause struct {
+	enabled   bool
+	target    []string
+	doNothing bool
+	updates   []conflictUpdate
+}
+
+// buildConflict construit la clause ON CONFLICT. Les valeurs du DO UPDATE SET sont
+// ajoutées à args, à la suite des valeurs insérées.
+func (c *conflictClause) buildConflict(args *arguments) string {
+	if !c.enabled {
+		return ""
+	}
+
+	clause := "ON CONFLICT"
+	if len(c.target) > 0 {
+		clause += " (" + strings.Join(c.target, ", ") + ")"
+	}
+
+	if c.doNothing || len(c.updates) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	if len(c.target) == 0 {
+		panic("ON CONFLICT DO UPDATE nécessite les colonnes de la contrainte en conflit")
+	}
+
+	setPairs := make([]string, len(c.updates))
+	for i, update := range c.updates {
+		if update.excluded {
+			setPairs[i] = fmt.Sprintf("%s = EXCLUDED.%s", update.column, update.column)
+		} else {
+			setPairs[i] = fmt.Sprintf("%s = %s", update.column, args.add(update.value))
+		}
+	}
+	return clause + " DO UPDATE SET " + strings.Join(setPairs, ", ")
+}
+
+// OnConflict ajoute une clause ON CONFLICT portant sur les colonnes d'une contrainte
+// unique ou de la clé primaire. Sans colonne, tout conflit est concerné (DO NOTHING
+// uniquement). Un nouvel appel remplace la clause précédente, actions comprises.
+// Sans DoUpdateSet ni DoUpdateExcluded, le conflit est ignoré (DO NOTHING).
+func (q *InsertQuery) OnConflict(columns ...string) *InsertQuery {
+	q.conflict = conflictClause{enabled: true, target: columns}
+	return q
+}
+
+// DoNothing ignore l'insertion en cas de conflit
+func (q *InsertQuery) DoNothing() *InsertQuery {
+	q.requireConflict("DoNothing")
+	q.conflict.doNothing = true
+	return q
+}
+
+// DoUpdateSet affecte une valeur à une colonne de la ligne existante en cas de conflit
+func (q *InsertQuery) DoUpdateSet(column string, value interface{}) *InsertQuery {
+	q.requireConflict("DoUpdateSet")
+	q.conflict.updates = append(q.conflict.updates, conflictUpdate{column: column, value: value})
+	return q
+}
+
+// DoUpdateExcluded remplace, en cas de conflit, les colonnes de la ligne existante par
+// les valeurs proposées à l'insertion (column = EXCLUDED.column)
+func (q *InsertQuery) DoUpdateExcluded(columns ...string) *InsertQuery {
+	q.requireConflict("DoUpdateExcluded")
+	for _, column := range columns {
+		q.conflict.updates = append(q.conflict.updates, conflictUpdate{column: column, excluded: true})
+	}
+	return q
+}
+
+// requireConflict vérifie que OnConflict a été appelé avant de définir une action
+func (q *InsertQuery) requireConflict(method string) {
+	if !q.conflict.enabled {
+		panic(fmt.Sprintf("%s doit être appelé après OnConflict pour la table %s", method, q.table))
+	}
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOnConflict(t *testing.T) {
+	insert := func() *InsertQuery {
+		return NewInsertQuery("users").AddColumn("email").AddValue("a@b.fr").AddColumn("name").AddValue("Ada")
+	}
+
+	tests := []struct {
+		name       string
+		query      *InsertQuery
+		want       string
+		wantValues []interface{}
+	}{
+		{
+			name:       "conflit ignoré",
+			query:      insert().OnConflict("email").DoNothing(),
+			want:       "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) DO NOTHING",
+			wantValues: []interface{}{"a@b.fr", "Ada"},
+		},
+		{
+			name:       "sans action",
+			query:      insert().OnConflict(),
+			want:       "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			wantValues: []interface{}{"a@b.fr", "Ada"},
+		},
+		{
+			name:       "valeurs affectées après les valeurs insérées",
+			query:      insert().OnConflict("email").DoUpdateSet("name", "Ada L.").DoUpdateSet("visits", 1).Returning("id"),
+			want:       "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET name = $3, visits = $4 RETURNING id",
+			wantValues: []interface{}{"a@b.fr", "Ada", "Ada L.", 1},
+		},
+		{
+			name:       "valeurs proposées",
+			query:      insert().OnConflict("email").DoUpdateExcluded("name"),
+			want:       "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name",
+			wantValues: []interface{}{"a@b.fr", "Ada"},
+		},
+		{
+			name:       "nouvel appel",
+			query:      insert().OnConflict("email").DoUpdateExcluded("name").OnConflict("name").DoNothing(),
+			want:       "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING",
+			wantValues: []interface{}{"a@b.fr", "Ada"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, values := tt.query.build()
+			if query != tt.want {
+				t.Errorf("requête = %s, attendu %s", query, tt.want)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("valeurs = %v, attendu %v", values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestOnConflictPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		query func()
+	}{
+		{"action sans OnConflict", func() { NewInsertQuery("users").DoNothing() }},
+		{"DO UPDATE sans colonnes", func() {
+			NewInsertQuery("users").AddColumn("email").AddValue("a").OnConflict().DoUpdateExcluded("email").build()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("panique attendue")
+				}
+			}()
+			tt.query()
+		})
+	}
+}
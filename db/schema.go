@@ -1,11 +1,16 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"postgo/logging"
+	"strings"
 )
 
-// Schema représente le registre global des tables
+// Schema représente un registre de tables. Une application crée son propre schéma avec
+// NewSchema et y enregistre ses tables avec Register ; plusieurs schémas indépendants
+// peuvent coexister. Les fonctions du package (InitAllTables, GetAllTables...) utilisent
+// le schéma par défaut, retourné par DefaultSchema.
 type Schema struct {
 	tables map[string]*TableBuilder
 	order  []string // Pour maintenir l'ordre de création
@@ -16,13 +21,49 @@ var globalSchema *Schema
 
 // init initialise automatiquement le schéma avec toutes les tables
 func init() {
-	globalSchema = &Schema{
+	globalSchema = NewSchema()
+
+	// Enregistrement automatique de toutes les tables
+	registerAllTables()
+}
+
+// NewSchema crée un schéma vide
+func NewSchema() *Schema {
+	return &Schema{
 		tables: make(map[string]*TableBuilder),
 		order:  make([]string, 0),
 	}
+}
 
-	// Enregistrement automatique de toutes les tables
-	registerAllTables()
+// DefaultSchema retourne le schéma par défaut, contenant les tables de db/schema.go
+func DefaultSchema() *Schema {
+	return globalSchema
+}
+
+// Register ajoute une table au schéma. Une table portant le même nom qu'une table
+// déjà enregistrée est refusée.
+func (s *Schema) Register(table *TableBuilder) error {
+	if table == nil {
+		return fmt.Errorf("impossible d'enregistrer une table nil")
+	}
+	if _, exists := s.tables[table.name]; exists {
+		return fmt.Errorf("la table '%s' est déjà enregistrée dans le schéma", table.name)
+	}
+	s.tables[table.name] = table
+	s.order = append(s.order, table.name)
+	logging.Info.Printf("Table '%s' enregistrée dans le schéma", table.name)
+	return nil
+}
+
+// MustRegister ajoute des tables au schéma comme Register et panique en cas de doublon.
+// Il retourne le schéma pour enchaîner les déclarations.
+func (s *Schema) MustRegister(tables ...*TableBuilder) *Schema {
+	for _, table := range tables {
+		if err := s.Register(table); err != nil {
+			panic(err)
+		}
+	}
+	return s
 }
 
 // registerAllTables enregistre toutes les tables du schéma
@@ -38,58 +79,212 @@ func registerAllTables() {
 	
 	// Table des catégories
 	registerTable("categories", createCategoryTable())
+
+	// Table de liaison entre posts et catégories
+	registerTable("post_categories", createPostCategoryTable())
 }
 
 // registerTable ajoute une table au registre global
 func registerTable(name string, builder *TableBuilder) {
-	globalSchema.tables[name] = builder
-	globalSchema.order = append(globalSchema.order, name)
-	logging.Info.Printf("Table '%s' enregistrée dans le schéma", name)
+	if name != builder.name {
+		panic(fmt.Sprintf("la table '%s' est enregistrée sous le nom '%s'", builder.name, name))
+	}
+	globalSchema.MustRegister(builder)
 }
 
-// InitAllTables crée toutes les tables enregistrées dans la base de données
+// InitAllTables crée toutes les tables du schéma par défaut dans la base de données
 func InitAllTables(conn *Connection) error {
+	return globalSchema.InitAllTables(conn)
+}
+
+// InitAllTablesContext crée toutes les tables du schéma par défaut en respectant le contexte
+func InitAllTablesContext(ctx context.Context, conn *Connection) error {
+	return globalSchema.InitAllTablesContext(ctx, conn)
+}
+
+// InitAllTablesWithOptions crée toutes les tables du schéma par défaut avec les options données
+func InitAllTablesWithOptions(ctx context.Context, conn *Connection, options InitOptions) error {
+	return globalSchema.InitAllTablesWithOptions(ctx, conn, options)
+}
+
+// InitOptions configure l'initialisation des tables par InitAllTablesWithOptions
+type InitOptions struct {
+	// ConcurrentIndexes crée les index avec CREATE INDEX CONCURRENTLY, sans bloquer les
+	// écritures sur les tables existantes. Les index sont alors créés hors transaction.
+	ConcurrentIndexes bool
+}
+
+// InitAllTables crée toutes les tables enregistrées dans le schéma
+func (s *Schema) InitAllTables(conn *Connection) error {
+	return s.InitAllTablesContext(context.Background(), conn)
+}
+
+// InitAllTablesContext crée toutes les tables enregistrées en respectant le contexte :
+// l'initialisation s'arrête à la première table dont la création est annulée ou expire
+func (s *Schema) InitAllTablesContext(ctx context.Context, conn *Connection) error {
+	return s.InitAllTablesWithOptions(ctx, conn, InitOptions{})
+}
+
+// InitAllTablesWithOptions crée les types énumérés, les tables puis leurs index, comme
+// InitAllTablesContext. Chaque élément est créé seulement s'il n'existe pas encore.
+func (s *Schema) InitAllTablesWithOptions(ctx context.Context, conn *Connection, options InitOptions) error {
 	logging.Info.Println("Initialisation de toutes les tables du schéma...")
+
+	order, err := s.creationOrder()
+	if err != nil {
+		return err
+	}
+
+	// Les types énumérés doivent exister avant les tables qui les utilisent
+	enums, err := s.enums(order)
+	if err != nil {
+		return err
+	}
+	for _, enum := range enums {
+		logging.Info.Printf("Création du type énuméré '%s'...", enum.GetName())
+		if err := conn.CreateEnumContext(ctx, enum); err != nil {
+			return fmt.Errorf("erreur lors de la création du type énuméré '%s': %w", enum.GetName(), err)
+		}
+	}
 	
-	for _, tableName := range globalSchema.order {
-		table := globalSchema.tables[tableName]
+	for _, tableName := range order {
+		table := s.tables[tableName]
 		
 		logging.Info.Printf("Création de la table '%s'...", tableName)
-		err := conn.CreateTable(table)
+		err := conn.CreateTableContext(ctx, table)
 		if err != nil {
-			return fmt.Errorf("erreur lors de la création de la table '%s': %v", tableName, err)
+			return fmt.Errorf("erreur lors de la création de la table '%s': %w", tableName, err)
 		}
 		logging.Info.Printf("Table '%s' créée avec succès!", tableName)
 	}
+
+	// Les index sont créés une fois toutes les tables en place
+	for _, tableName := range order {
+		for _, index := range s.tables[tableName].indexes {
+			logging.Info.Printf("Création de l'index '%s'...", index.name)
+			if err := conn.createIndex(ctx, index, options.ConcurrentIndexes); err != nil {
+				return fmt.Errorf("erreur lors de la création de l'index '%s': %w", index.name, err)
+			}
+		}
+	}
 	
-	logging.Info.Printf("Toutes les tables (%d) ont été créées avec succès!", len(globalSchema.order))
+	logging.Info.Printf("Toutes les tables (%d) ont été créées avec succès!", len(s.order))
 	return nil
 }
 
-// GetTable retourne une table spécifique du schéma
+// creationOrder retourne les noms des tables triés de sorte que chaque table
+// soit créée après les tables qu'elle référence. L'ordre d'enregistrement est
+// conservé entre les tables indépendantes. Un cycle de clés étrangères, une
+// référence vers une table inconnue, une contrainte portant sur une colonne
+// inconnue ou une valeur par défaut invalide est signalé par une erreur.
+func (s *Schema) creationOrder() ([]string, error) {
+	remaining := make(map[string]int, len(s.order))
+	dependents := make(map[string][]string)
+
+	for _, tableName := range s.order {
+		if err := s.tables[tableName].validateConstraints(); err != nil {
+			return nil, err
+		}
+		if err := s.tables[tableName].validateDefaults(); err != nil {
+			return nil, err
+		}
+
+		dependencies := s.tables[tableName].GetDependencies()
+		for _, dependency := range dependencies {
+			if _, exists := s.tables[dependency]; !exists {
+				return nil, fmt.Errorf("la table '%s' référence la table inconnue '%s'", tableName, dependency)
+			}
+			dependents[dependency] = append(dependents[dependency], tableName)
+		}
+		remaining[tableName] = len(dependencies)
+	}
+
+	order := make([]string, 0, len(s.order))
+	created := make(map[string]bool, len(s.order))
+	for len(order) < len(s.order) {
+		progressed := false
+		for _, tableName := range s.order {
+			if created[tableName] || remaining[tableName] > 0 {
+				continue
+			}
+			created[tableName] = true
+			order = append(order, tableName)
+			for _, dependent := range dependents[tableName] {
+				remaining[dependent]--
+			}
+			progressed = true
+		}
+
+		if !progressed {
+			var cycle []string
+			for _, tableName := range s.order {
+				if !created[tableName] {
+					cycle = append(cycle, tableName)
+				}
+			}
+			return nil, fmt.Errorf("cycle de clés étrangères détecté, tables non résolues: %s", strings.Join(cycle, ", "))
+		}
+	}
+
+	return order, nil
+}
+
+// GetTable retourne une table spécifique du schéma par défaut
 func GetTable(name string) (*TableBuilder, bool) {
-	table, exists := globalSchema.tables[name]
+	return globalSchema.GetTable(name)
+}
+
+// GetAllTables retourne toutes les tables du schéma par défaut
+func GetAllTables() map[string]*TableBuilder {
+	return globalSchema.GetAllTables()
+}
+
+// ListTables retourne la liste des noms de tables du schéma par défaut dans l'ordre d'enregistrement
+func ListTables() []string {
+	return globalSchema.ListTables()
+}
+
+// GetTable retourne une table spécifique du schéma
+func (s *Schema) GetTable(name string) (*TableBuilder, bool) {
+	table, exists := s.tables[name]
 	return table, exists
 }
 
 // GetAllTables retourne toutes les tables du schéma
-func GetAllTables() map[string]*TableBuilder {
-	return globalSchema.tables
+func (s *Schema) GetAllTables() map[string]*TableBuilder {
+	return s.tables
 }
 
 // ListTables retourne la liste des noms de tables dans l'ordre d'enregistrement
-func ListTables() []string {
-	return globalSchema.order
+func (s *Schema) ListTables() []string {
+	return s.order
+}
+
+// Validate vérifie la cohérence du schéma : références vers des tables enregistrées,
+// absence de cycle de clés étrangères, contraintes et index portant sur des colonnes
+// déclarées, valeurs par défaut valides et types énumérés homonymes identiques
+func (s *Schema) Validate() error {
+	order, err := s.creationOrder()
+	if err != nil {
+		return err
+	}
+	_, err = s.enums(order)
+	return err
 }
 
 // === DÉFINITIONS DES TABLES ===
 
+// postStatus est le type énuméré du statut éditorial d'un post
+var postStatus = NewEnum("post_status", "draft", "published", "archived")
+
 // createUserTable crée la définition de la table users
 func createUserTable() *TableBuilder {
 	return NewTable("users").
 		AddAttribute("name", String).NotNull().Build().
 		AddAttribute("email", String).NotNull().Unique().Build().
-		AddAttribute("password", String).NotNull().Build()
+		AddAttribute("password", String).NotNull().Build().
+		AddAttribute("created_at", TimestampTZ).NotNull().DefaultExpr("now()").Build().
+		AddExpressionIndex("users_email_lower_idx", "lower(email)").Build()
 }
 
 // createCompanyTable crée la définition de la table companies
@@ -97,9 +292,11 @@ func createCompanyTable() *TableBuilder {
 	return NewTable("companies").
 		AddAttribute("name", String).NotNull().Unique().Build().
 		AddAttribute("description", String).Build().
-		AddAttribute("employee_count", Integer).Build().
+		AddAttribute("employee_count", Integer).Check("employee_count >= 0").Build().
 		AddAttribute("revenue", Float).Build().
-		AddAttribute("is_public", Boolean).NotNull().Build()
+		AddAttribute("is_public", Boolean).NotNull().Default(false).Build().
+		AddAttribute("capital", NumericOf(15, 2)).Build().
+		AddAttribute("revenue_per_employee", Float).GeneratedAlwaysAs("revenue / NULLIF(employee_count, 0)").Build()
 }
 
 // createPostTable crée la définition de la table posts
@@ -107,7 +304,18 @@ func createPostTable() *TableBuilder {
 	return NewTable("posts").
 		AddAttribute("title", String).NotNull().Build().
 		AddAttribute("content", String).Build().
-		AddAttribute("published", Boolean).Build()
+		AddAttribute("published", Boolean).Build().
+		AddAttribute("author_id", Integer).References("users", "id").OnDelete(Cascade).Build().
+		AddAttribute("company_id", Integer).References("companies", "id").OnDelete(SetNull).Build().
+		AddAttribute("published_at", TimestampTZ).Build().
+		AddAttribute("metadata", JSONB).Build().
+		AddAttribute("tags", ArrayOf(Text)).Build().
+		AddEnumAttribute("status", postStatus).Build().
+		AddAttribute("slug", String).Build().
+		Unique("company_id", "slug").
+		AddIndex("published_at").Where("published").Build().
+		AddIndex("author_id", "published").Build().
+		AddIndex("tags").Using(GIN).Build()
 }
 
 // createCategoryTable crée la définition de la table categories
@@ -116,3 +324,12 @@ func createCategoryTable() *TableBuilder {
 		AddAttribute("slug", String).NotNull().Unique().Build().
 		AddAttribute("display_name", String).NotNull().Build()
 }
+
+// createPostCategoryTable crée la définition de la table de liaison post_categories,
+// sans colonne id : la clé primaire est le couple (post_id, category_id)
+func createPostCategoryTable() *TableBuilder {
+	return NewTable("post_categories").
+		AddAttribute("post_id", Integer).NotNull().References("posts", "id").OnDelete(Cascade).Build().
+		AddAttribute("category_id", Integer).NotNull().References("categories", "id").OnDelete(Cascade).Build().
+		PrimaryKey("post_id", "category_id")
+}
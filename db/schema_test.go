@@ -0,0 +1,85 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// referencing retourne une table dont chaque colonne <table>_id référence la table donnée
+func referencing(name string, tables ...string) *TableBuilder {
+	tb := NewTable(name)
+	for _, table := range tables {
+		tb.AddAttribute(table+"_id", Integer).References(table, "id").Build()
+	}
+	return tb
+}
+
+func TestCreationOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		tables  []*TableBuilder
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "références déclarées après",
+			tables: []*TableBuilder{referencing("posts", "users", "companies"), referencing("users", "companies"), referencing("companies")},
+			want:   []string{"companies", "users", "posts"},
+		},
+		{
+			name:   "ordre d'enregistrement conservé",
+			tables: []*TableBuilder{referencing("tags"), referencing("users"), referencing("categories")},
+			want:   []string{"tags", "users", "categories"},
+		},
+		{
+			name:   "auto-référence",
+			tables: []*TableBuilder{referencing("categories", "categories")},
+			want:   []string{"categories"},
+		},
+		{
+			name:    "cycle",
+			tables:  []*TableBuilder{referencing("users", "teams"), referencing("teams", "users"), referencing("tags")},
+			wantErr: "cycle de clés étrangères détecté, tables non résolues: users, teams",
+		},
+		{
+			name:    "cycle indirect",
+			tables:  []*TableBuilder{referencing("a", "c"), referencing("b", "a"), referencing("c", "b")},
+			wantErr: "tables non résolues: a, b, c",
+		},
+		{
+			name:    "table inconnue",
+			tables:  []*TableBuilder{referencing("posts", "authors")},
+			wantErr: "la table 'posts' référence la table inconnue 'authors'",
+		},
+		{
+			name:    "contrainte sur une colonne inconnue",
+			tables:  []*TableBuilder{referencing("posts").Unique("slug")},
+			wantErr: "porte sur la colonne inconnue 'slug'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := NewSchema()
+			for _, table := range tt.tables {
+				if err := schema.Register(table); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := schema.creationOrder()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ordre = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
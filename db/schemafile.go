@@ -0,0 +1,600 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaFileError décrit une erreur d'un fichier de schéma déclaratif, située par sa ligne
+type SchemaFileError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+// Error retourne l'erreur au format fichier:ligne: message
+func (e *SchemaFileError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// LoadSchemaFile lit un schéma déclaratif YAML (.yaml, .yml) ou JSON (.json) :
+//
+//	enums:
+//	  post_status: [draft, published, archived]
+//	tables:
+//	  - name: posts
+//	    primary_key: bigserial          # serial, bigserial, identity, uuid, none ou [col, ...]
+//	    columns:
+//	      - {name: title, type: varchar(200), not_null: true, check: "title <> ''"}
+//	      - {name: author_id, type: integer, references: {table: users, column: id, on_delete: cascade}}
+//	      - {name: status, type: post_status, not_null: true, default: "'draft'"}
+//	    unique: [[author_id, title]]
+//	    checks: [{name: posts_title_check, expression: "length(title) > 3"}]
+//	    indexes:
+//	      - {columns: [published_at], where: published}
+//	      - {name: posts_tags_idx, columns: [tags], method: gin}
+//
+// La validation est stricte : une clé inconnue, un type inconnu ou une référence vers une
+// colonne absente est une erreur. Toutes les erreurs trouvées sont retournées ensemble,
+// chacune sous la forme d'une *SchemaFileError.
+func LoadSchemaFile(path string) (*Schema, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadSchema(file, path)
+}
+
+// LoadSchema lit un schéma déclaratif YAML ou JSON comme LoadSchemaFile. Le nom sert
+// uniquement à situer les erreurs.
+func LoadSchema(r io.Reader, name string) (*Schema, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Le JSON est lu comme du YAML, qui n'accepte pas les tabulations d'indentation.
+	// Une chaîne JSON ne pouvant pas contenir de tabulation brute, les remplacer est sans effet.
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		content = []byte(strings.ReplaceAll(string(content), "\t", " "))
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	loader := &schemaLoader{file: name, enums: make(map[string]*Enum)}
+	schema := loader.load(&root)
+	if len(loader.errors) > 0 {
+		return nil, errors.Join(loader.errors...)
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return schema, nil
+}
+
+// schemaLoader construit un Schema à partir de l'arbre YAML en accumulant les erreurs
+type schemaLoader struct {
+	file   string
+	enums  map[string]*Enum
+	errors []error
+}
+
+// identifierPattern valide les noms de tables, colonnes, contraintes, index et types énumérés
+var identifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// typeParamsPattern valide les paramètres d'un type (ex: "(255)", "(15,2)")
+var typeParamsPattern = regexp.MustCompile(`^\(\d+(,\d+)?\)$`)
+
+// errorf enregistre une erreur située à la ligne du nœud
+func (l *schemaLoader) errorf(node *yaml.Node, format string, args ...interface{}) {
+	l.errors = append(l.errors, &SchemaFileError{File: l.file, Line: node.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+// fields vérifie qu'un nœud est une table de clés connues et retourne ses valeurs par clé
+func (l *schemaLoader) fields(node *yaml.Node, what string, allowed ...string) map[string]*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, "%s doit être un objet", what)
+		return nil
+	}
+
+	fields := make(map[string]*yaml.Node, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch {
+		case !slices.Contains(allowed, key.Value):
+			l.errorf(key, "clé inconnue %q dans %s (clés acceptées: %s)", key.Value, what, strings.Join(allowed, ", "))
+		case fields[key.Value] != nil:
+			l.errorf(key, "clé %q définie plusieurs fois dans %s", key.Value, what)
+		default:
+			fields[key.Value] = value
+		}
+	}
+	return fields
+}
+
+// required retourne la valeur d'une clé obligatoire, ou nil après avoir enregistré l'erreur
+func (l *schemaLoader) required(node *yaml.Node, fields map[string]*yaml.Node, key, what string) *yaml.Node {
+	value := fields[key]
+	if value == nil && fields != nil {
+		l.errorf(node, "clé %q obligatoire dans %s", key, what)
+	}
+	return value
+}
+
+// scalar lit une valeur scalaire non vide
+func (l *schemaLoader) scalar(node *yaml.Node, what string) (string, bool) {
+	if node.Kind != yaml.ScalarNode || node.Tag == "!!null" || node.Value == "" {
+		l.errorf(node, "%s doit être une valeur non vide", what)
+		return "", false
+	}
+	return node.Value, true
+}
+
+// identifier lit un nom SQL en minuscules (table, colonne, contrainte...)
+func (l *schemaLoader) identifier(node *yaml.Node, what string) (string, bool) {
+	value, ok := l.scalar(node, what)
+	if ok && !identifierPattern.MatchString(value) {
+		l.errorf(node, "%s %q invalide: lettres minuscules, chiffres et _ uniquement", what, value)
+		return "", false
+	}
+	return value, ok
+}
+
+// boolean lit un booléen YAML
+func (l *schemaLoader) boolean(node *yaml.Node, what string) bool {
+	var value bool
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" || node.Decode(&value) != nil {
+		l.errorf(node, "%s doit valoir true ou false", what)
+	}
+	return value
+}
+
+// identifiers lit une liste non vide de noms
+func (l *schemaLoader) identifiers(node *yaml.Node, what string) []string {
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		l.errorf(node, "%s doit être une liste non vide", what)
+		return nil
+	}
+	names := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		if name, ok := l.identifier(item, what); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// load lit le document : les types énumérés puis les tables
+func (l *schemaLoader) load(root *yaml.Node) *Schema {
+	schema := NewSchema()
+	if len(root.Content) == 0 {
+		l.errorf(root, "le schéma est vide")
+		return schema
+	}
+
+	document := root.Content[0]
+	fields := l.fields(document, "le schéma", "enums", "tables")
+	if enums := fields["enums"]; enums != nil {
+		l.loadEnums(enums)
+	}
+
+	tablesNode := l.required(document, fields, "tables", "le schéma")
+	if tablesNode == nil {
+		return schema
+	}
+	if tablesNode.Kind != yaml.SequenceNode {
+		l.errorf(tablesNode, "tables doit être une liste")
+		return schema
+	}
+
+	// Les noms de toutes les tables sont connus avant de vérifier les clés étrangères
+	lines := make(map[string]int)
+	for _, tableNode := range tablesNode.Content {
+		if tableNode.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(tableNode.Content); i += 2 {
+			if tableNode.Content[i].Value == "name" {
+				if _, exists := lines[tableNode.Content[i+1].Value]; !exists {
+					lines[tableNode.Content[i+1].Value] = tableNode.Line
+				}
+			}
+		}
+	}
+
+	for _, tableNode := range tablesNode.Content {
+		table := l.loadTable(tableNode, lines)
+		if table == nil {
+			continue
+		}
+		if err := schema.Register(table); err != nil {
+			l.errorf(tableNode, "la table %q est déclarée plusieurs fois (première déclaration ligne %d)", table.name, lines[table.name])
+		}
+	}
+	return schema
+}
+
+// loadEnums lit les types énumérés, déclarés par nom avec la liste de leurs valeurs
+func (l *schemaLoader) loadEnums(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node, "enums doit associer chaque type énuméré à ses valeurs")
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, values := node.Content[i], node.Content[i+1]
+		name, ok := l.identifier(key, "le nom du type énuméré")
+		if !ok {
+			continue
+		}
+		if _, exists := l.enums[name]; exists {
+			l.errorf(key, "type énuméré %q déclaré plusieurs fois", name)
+			continue
+		}
+		if values.Kind != yaml.SequenceNode || len(values.Content) == 0 {
+			l.errorf(values, "le type énuméré %q doit avoir une liste non vide de valeurs", name)
+			continue
+		}
+		var enumValues []string
+		for _, value := range values.Content {
+			if v, ok := l.scalar(value, "une valeur de type énuméré"); ok {
+				if slices.Contains(enumValues, v) {
+					l.errorf(value, "valeur %q en double dans le type énuméré %q", v, name)
+				}
+				enumValues = append(enumValues, v)
+			}
+		}
+		if len(enumValues) > 0 {
+			l.enums[name] = NewEnum(name, enumValues...)
+		}
+	}
+}
+
+// loadTable lit une table et retourne nil si elle est inutilisable
+func (l *schemaLoader) loadTable(node *yaml.Node, tables map[string]int) *TableBuilder {
+	fields := l.fields(node, "une table", "name", "primary_key", "columns", "unique", "checks", "indexes")
+	nameNode := l.required(node, fields, "name", "une table")
+	if nameNode == nil {
+		return nil
+	}
+	name, ok := l.identifier(nameNode, "le nom de table")
+	if !ok {
+		return nil
+	}
+
+	tb := NewTable(name)
+	columns := make(map[string]bool)
+
+	// La stratégie de clé est appliquée avant les colonnes, la clé composite après
+	var keyColumns []string
+	var keyNode *yaml.Node
+	if keyNode = fields["primary_key"]; keyNode != nil {
+		keyColumns = l.loadKeyStrategy(tb, keyNode)
+	}
+	if tb.key != "" {
+		columns["id"] = true
+	}
+
+	columnsNode := l.required(node, fields, "columns", fmt.Sprintf("la table %q", name))
+	if columnsNode != nil {
+		if columnsNode.Kind != yaml.SequenceNode || len(columnsNode.Content) == 0 {
+			l.errorf(columnsNode, "columns doit être une liste non vide")
+		} else {
+			for _, columnNode := range columnsNode.Content {
+				l.loadColumn(tb, columnNode, columns, tables)
+			}
+		}
+	}
+
+	checkColumns := func(node *yaml.Node, names []string, what string) bool {
+		valid := true
+		for _, column := range names {
+			if !columns[column] {
+				l.errorf(node, "%s porte sur la colonne inconnue %q de la table %q", what, column, name)
+				valid = false
+			}
+		}
+		return valid
+	}
+
+	if keyColumns != nil && checkColumns(keyNode, keyColumns, "la clé primaire") {
+		tb.PrimaryKey(keyColumns...)
+	}
+
+	if uniqueNode := fields["unique"]; uniqueNode != nil {
+		if uniqueNode.Kind != yaml.SequenceNode {
+			l.errorf(uniqueNode, "unique doit être une liste de listes de colonnes")
+		} else {
+			for _, item := range uniqueNode.Content {
+				if names := l.identifiers(item, "une contrainte unique"); names != nil && checkColumns(item, names, "la contrainte unique") {
+					tb.Unique(names...)
+				}
+			}
+		}
+	}
+
+	if checksNode := fields["checks"]; checksNode != nil {
+		if checksNode.Kind != yaml.SequenceNode {
+			l.errorf(checksNode, "checks doit être une liste")
+		} else {
+			for _, item := range checksNode.Content {
+				checkFields := l.fields(item, "une contrainte CHECK", "name", "expression")
+				nameNode := l.required(item, checkFields, "name", "une contrainte CHECK")
+				expressionNode := l.required(item, checkFields, "expression", "une contrainte CHECK")
+				if nameNode == nil || expressionNode == nil {
+					continue
+				}
+				checkName, nameOK := l.identifier(nameNode, "le nom de contrainte")
+				expression, expressionOK := l.scalar(expressionNode, "l'expression CHECK")
+				if nameOK && expressionOK {
+					tb.Check(checkName, expression)
+				}
+			}
+		}
+	}
+
+	if indexesNode := fields["indexes"]; indexesNode != nil {
+		if indexesNode.Kind != yaml.SequenceNode {
+			l.errorf(indexesNode, "indexes doit être une liste")
+		} else {
+			for _, item := range indexesNode.Content {
+				l.loadIndex(tb, item, checkColumns)
+			}
+		}
+	}
+
+	return tb
+}
+
+// keyStrategies associe les valeurs de primary_key aux stratégies de clé
+var keyStrategies = map[string]KeyStrategy{
+	"serial":    SerialKey,
+	"bigserial": BigSerialKey,
+	"identity":  IdentityKey,
+	"uuid":      UUIDKey,
+}
+
+// loadKeyStrategy applique primary_key : une stratégie de colonne id, none, ou la liste des
+// colonnes d'une clé naturelle ou composite, retournée pour être appliquée après les colonnes
+func (l *schemaLoader) loadKeyStrategy(tb *TableBuilder, node *yaml.Node) []string {
+	if node.Kind == yaml.SequenceNode {
+		tb.WithoutKey()
+		return l.identifiers(node, "primary_key")
+	}
+
+	value, ok := l.scalar(node, "primary_key")
+	if !ok {
+		return nil
+	}
+	if value == "none" {
+		tb.WithoutKey()
+		return nil
+	}
+	strategy, known := keyStrategies[value]
+	if !known {
+		l.errorf(node, "primary_key %q inconnu (serial, bigserial, identity, uuid, none ou liste de colonnes)", value)
+		return nil
+	}
+	tb.WithKey(strategy)
+	return nil
+}
+
+// loadColumn lit une colonne et l'ajoute à la table
+func (l *schemaLoader) loadColumn(tb *TableBuilder, node *yaml.Node, columns map[string]bool, tables map[string]int) {
+	fields := l.fields(node, "une colonne", "name", "type", "not_null", "unique", "default", "generated", "check", "references")
+	nameNode := l.required(node, fields, "name", "une colonne")
+	typeNode := l.required(node, fields, "type", "une colonne")
+	if nameNode == nil || typeNode == nil {
+		return
+	}
+	name, ok := l.identifier(nameNode, "le nom de colonne")
+	if !ok {
+		return
+	}
+	if columns[name] {
+		l.errorf(nameNode, "la colonne %q est déclarée plusieurs fois dans la table %q", name, tb.name)
+		return
+	}
+	columns[name] = true
+
+	typeName, ok := l.scalar(typeNode, "le type")
+	if !ok {
+		return
+	}
+	var ab *AttributeBuilder
+	if enum, isEnum := l.enums[typeName]; isEnum {
+		ab = tb.AddEnumAttribute(name, enum)
+	} else if dataType, valid := parseAttributeType(typeName); valid {
+		ab = tb.AddAttribute(name, dataType)
+	} else {
+		l.errorf(typeNode, "type %q inconnu pour la colonne %q (type SQL ou type énuméré déclaré dans enums)", typeName, name)
+		return
+	}
+
+	if value := fields["not_null"]; value != nil && l.boolean(value, "not_null") {
+		ab.NotNull()
+	}
+	if value := fields["unique"]; value != nil && l.boolean(value, "unique") {
+		ab.Unique()
+	}
+	if value := fields["default"]; value != nil {
+		if expr, ok := l.scalar(value, "default"); ok {
+			ab.DefaultExpr(expr)
+		}
+	}
+	if value := fields["generated"]; value != nil {
+		if expr, ok := l.scalar(value, "generated"); ok {
+			if fields["default"] != nil {
+				l.errorf(value, "la colonne calculée %q ne peut pas avoir de valeur par défaut", name)
+			} else {
+				ab.GeneratedAlwaysAs(expr)
+			}
+		}
+	}
+	if value := fields["check"]; value != nil {
+		if expr, ok := l.scalar(value, "check"); ok {
+			ab.Check(expr)
+		}
+	}
+	if value := fields["references"]; value != nil {
+		l.loadReference(ab, value, tables)
+	}
+
+	ab.Build()
+}
+
+// referentialActions associe les valeurs de on_delete et on_update aux actions référentielles
+var referentialActions = map[string]ReferentialAction{
+	"cascade":  Cascade,
+	"set_null": SetNull,
+	"restrict": Restrict,
+}
+
+// loadReference lit la clé étrangère d'une colonne
+func (l *schemaLoader) loadReference(ab *AttributeBuilder, node *yaml.Node, tables map[string]int) {
+	fields := l.fields(node, "references", "table", "column", "on_delete", "on_update")
+	tableNode := l.required(node, fields, "table", "references")
+	if tableNode == nil {
+		return
+	}
+	table, ok := l.identifier(tableNode, "la table référencée")
+	if !ok {
+		return
+	}
+	if _, exists := tables[table]; !exists {
+		l.errorf(tableNode, "la colonne %q référence la table inconnue %q", ab.attribute.name, table)
+		return
+	}
+
+	column := "id"
+	if columnNode := fields["column"]; columnNode != nil {
+		if column, ok = l.identifier(columnNode, "la colonne référencée"); !ok {
+			return
+		}
+	}
+	ab.References(table, column)
+
+	if action, ok := l.referentialAction(fields["on_delete"], "on_delete"); ok {
+		ab.OnDelete(action)
+	}
+	if action, ok := l.referentialAction(fields["on_update"], "on_update"); ok {
+		ab.OnUpdate(action)
+	}
+}
+
+// referentialAction lit l'action référentielle d'une clé on_delete ou on_update, si elle est définie
+func (l *schemaLoader) referentialAction(node *yaml.Node, key string) (ReferentialAction, bool) {
+	if node == nil {
+		return "", false
+	}
+	action, known := referentialActions[node.Value]
+	if !known {
+		l.errorf(node, "%s %q inconnu (cascade, set_null ou restrict)", key, node.Value)
+	}
+	return action, known
+}
+
+// indexMethods liste les méthodes d'accès acceptées par method
+var indexMethods = []IndexMethod{BTree, Hash, GIN, GiST, BRIN}
+
+// loadIndex lit un index, sur des colonnes ou sur une expression
+func (l *schemaLoader) loadIndex(tb *TableBuilder, node *yaml.Node, checkColumns func(*yaml.Node, []string, string) bool) {
+	fields := l.fields(node, "un index", "name", "columns", "expression", "unique", "method", "where")
+	if fields == nil {
+		return
+	}
+
+	var ib *IndexBuilder
+	switch columnsNode, expressionNode := fields["columns"], fields["expression"]; {
+	case columnsNode != nil && expressionNode != nil:
+		l.errorf(node, "un index porte sur columns ou sur expression, pas les deux")
+		return
+	case columnsNode != nil:
+		columns := l.identifiers(columnsNode, "columns")
+		if columns == nil || !checkColumns(columnsNode, columns, "l'index") {
+			return
+		}
+		ib = tb.AddIndex(columns...)
+		if nameNode := fields["name"]; nameNode != nil {
+			if name, ok := l.identifier(nameNode, "le nom d'index"); ok {
+				ib.Name(name)
+			}
+		}
+	case expressionNode != nil:
+		nameNode := l.required(node, fields, "name", "un index sur expression")
+		if nameNode == nil {
+			return
+		}
+		name, nameOK := l.identifier(nameNode, "le nom d'index")
+		expression, expressionOK := l.scalar(expressionNode, "expression")
+		if !nameOK || !expressionOK {
+			return
+		}
+		ib = tb.AddExpressionIndex(name, expression)
+	default:
+		l.errorf(node, "un index doit définir columns ou expression")
+		return
+	}
+
+	if value := fields["unique"]; value != nil && l.boolean(value, "unique") {
+		ib.Unique()
+	}
+	if value := fields["method"]; value != nil {
+		if method := IndexMethod(value.Value); slices.Contains(indexMethods, method) {
+			ib.Using(method)
+		} else {
+			l.errorf(value, "method %q inconnue (btree, hash, gin, gist ou brin)", value.Value)
+		}
+	}
+	if value := fields["where"]; value != nil {
+		if predicate, ok := l.scalar(value, "where"); ok {
+			ib.Where(predicate)
+		}
+	}
+
+	if slices.ContainsFunc(tb.indexes, func(index *Index) bool { return index.name == ib.index.name }) {
+		l.errorf(node, "l'index %q est déclaré plusieurs fois sur la table %q", ib.index.name, tb.name)
+		return
+	}
+	ib.Build()
+}
+
+// parseAttributeType convertit un type SQL écrit dans un fichier de schéma en AttributeType
+// (ex: "varchar(100)" -> VARCHAR(100), "text[]" -> TEXT[]). Le type "string" désigne String.
+func parseAttributeType(typeName string) (AttributeType, bool) {
+	typeName = strings.TrimSpace(typeName)
+	if strings.HasSuffix(typeName, "[]") {
+		elementType, valid := parseAttributeType(strings.TrimSuffix(typeName, "[]"))
+		return ArrayOf(elementType), valid && !strings.HasSuffix(string(elementType), "[]")
+	}
+	if strings.EqualFold(typeName, "string") {
+		return String, true
+	}
+
+	base, params := typeName, ""
+	if open := strings.Index(typeName, "("); open >= 0 {
+		base = strings.TrimSpace(typeName[:open])
+		params = strings.ReplaceAll(typeName[open:], " ", "")
+		if !typeParamsPattern.MatchString(params) {
+			return "", false
+		}
+	}
+
+	base = strings.ToUpper(strings.Join(strings.Fields(base), " "))
+	if _, known := canonicalTypeNames[base]; !known {
+		return "", false
+	}
+	return AttributeType(base + params), true
+}
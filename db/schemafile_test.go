@@ -0,0 +1,114 @@
+package db
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadSchemaErrorLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name: "clé inconnue et type inconnu",
+			source: `tables:
+  - name: users
+    columns:
+      - {name: email, type: varchar(255)}
+      - {name: age, type: integr}
+    colums: []
+`,
+			want: []string{
+				`users.yaml:6: clé inconnue "colums" dans une table`,
+				`users.yaml:5: type "integr" inconnu pour la colonne "age"`,
+			},
+		},
+		{
+			name: "référence vers une table inconnue",
+			source: `tables:
+  - name: posts
+    columns:
+      - name: author_id
+        type: integer
+        references:
+          table: authors
+`,
+			want: []string{`users.yaml:7: la colonne "author_id" référence la table inconnue "authors"`},
+		},
+		{
+			name: "table déclarée deux fois",
+			source: `tables:
+  - name: tags
+    columns: [{name: label, type: text}]
+
+  - name: tags
+    columns: [{name: label, type: text}]
+`,
+			want: []string{`users.yaml:5: la table "tags" est déclarée plusieurs fois (première déclaration ligne 2)`},
+		},
+		{
+			name: "colonne en double et clé obligatoire",
+			source: `enums:
+  post_status: []
+tables:
+  - name: posts
+    columns:
+      - {name: title, type: text}
+      - {name: title, type: text}
+      - {type: text}
+`,
+			want: []string{
+				`users.yaml:2: le type énuméré "post_status" doit avoir une liste non vide de valeurs`,
+				`users.yaml:7: la colonne "title" est déclarée plusieurs fois dans la table "posts"`,
+				`users.yaml:8: clé "name" obligatoire dans une colonne`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSchema(strings.NewReader(tt.source), "users.yaml")
+			if err == nil {
+				t.Fatal("erreur attendue")
+			}
+
+			// errors.Join sépare les erreurs par un saut de ligne, dans l'ordre de lecture
+			got := strings.Split(err.Error(), "\n")
+			if len(got) != len(tt.want) {
+				t.Fatalf("erreurs =\n%s\nattendu %d erreur(s)", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("erreur %d = %q, attendu %q", i, got[i], want)
+				}
+			}
+
+			// Chaque erreur est une *SchemaFileError située dans le fichier
+			var fileErr *SchemaFileError
+			if !errors.As(err, &fileErr) || fileErr.File != "users.yaml" || fileErr.Line == 0 {
+				t.Errorf("SchemaFileError attendue, obtenu %#v", fileErr)
+			}
+		})
+	}
+}
+
+func TestLoadSchemaSyntaxError(t *testing.T) {
+	_, err := LoadSchema(strings.NewReader("tables:\n  - name: users\n    name: : x\n"), "broken.yaml")
+	if err == nil || !strings.Contains(err.Error(), "broken.yaml: yaml: line 3") {
+		t.Fatalf("erreur = %v, attendu une erreur de syntaxe ligne 3", err)
+	}
+}
+
+func TestLoadSchemaJSON(t *testing.T) {
+	source := "{\n\t\"tables\": [\n\t\t{\"name\": \"users\", \"columns\": [{\"name\": \"email\", \"type\": \"text\", \"not_null\": true}]}\n\t]\n}\n"
+	schema, err := LoadSchema(strings.NewReader(source), "schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(schema.ListTables(), []string{"users"}) {
+		t.Errorf("tables = %v", schema.ListTables())
+	}
+}
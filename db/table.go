@@ -1,8 +1,13 @@
 package db
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
 )
@@ -11,17 +16,113 @@ import (
 type AttributeType string
 
 const (
-	String  AttributeType = "VARCHAR(255)"
-	Integer AttributeType = "INTEGER"
-	Float   AttributeType = "FLOAT"
-	Boolean AttributeType = "BOOLEAN"
+	String      AttributeType = "VARCHAR(255)"
+	Text        AttributeType = "TEXT"
+	SmallInt    AttributeType = "SMALLINT"
+	Integer     AttributeType = "INTEGER"
+	BigInt      AttributeType = "BIGINT"
+	Float       AttributeType = "FLOAT"
+	Numeric     AttributeType = "NUMERIC"
+	Boolean     AttributeType = "BOOLEAN"
+	Date        AttributeType = "DATE"
+	Timestamp   AttributeType = "TIMESTAMP"
+	TimestampTZ AttributeType = "TIMESTAMPTZ"
+	UUID        AttributeType = "UUID"
+	JSON        AttributeType = "JSON"
+	JSONB       AttributeType = "JSONB"
+	Bytea       AttributeType = "BYTEA"
 )
 
+// Varchar retourne le type VARCHAR(length), chaîne de longueur maximale length
+func Varchar(length int) AttributeType {
+	return AttributeType(fmt.Sprintf("VARCHAR(%d)", length))
+}
+
+// Char retourne le type CHAR(length), chaîne de longueur fixe length
+func Char(length int) AttributeType {
+	return AttributeType(fmt.Sprintf("CHAR(%d)", length))
+}
+
+// NumericOf retourne le type NUMERIC(precision, scale) : precision chiffres au total,
+// dont scale après la virgule (ex: NumericOf(12, 2) pour des montants)
+func NumericOf(precision, scale int) AttributeType {
+	return AttributeType(fmt.Sprintf("NUMERIC(%d,%d)", precision, scale))
+}
+
+// ArrayOf retourne le type tableau PostgreSQL dont les éléments sont du type donné
+// (ex: ArrayOf(Text) pour TEXT[])
+func ArrayOf(elementType AttributeType) AttributeType {
+	return elementType + "[]"
+}
+
+// goTypes associe les types PostgreSQL, sous leur forme canonique sans paramètres,
+// aux types Go utilisés par le code généré
+var goTypes = map[string]string{
+	"character varying":           "string",
+	"character":                   "string",
+	"text":                        "string",
+	"smallint":                    "int",
+	"integer":                     "int",
+	"bigint":                      "int64",
+	"real":                        "float64",
+	"double precision":            "float64",
+	"numeric":                     "db.Decimal",
+	"boolean":                     "bool",
+	"date":                        "time.Time",
+	"timestamp without time zone": "time.Time",
+	"timestamp with time zone":    "time.Time",
+	"uuid":                        "string",
+	"json":                        "json.RawMessage",
+	"jsonb":                       "json.RawMessage",
+	"bytea":                       "[]byte",
+}
+
+// arrayGoTypes associe le type Go des éléments d'un tableau au slice lu et écrit par
+// les helpers pq.Array de lib/pq
+var arrayGoTypes = map[string]string{
+	"string":  "[]string",
+	"int":     "[]int64",
+	"int64":   "[]int64",
+	"float64": "[]float64",
+	"bool":    "[]bool",
+}
+
+// ReferentialAction représente l'action appliquée par une clé étrangère
+// lorsque la ligne référencée est supprimée ou modifiée
+type ReferentialAction string
+
+const (
+	Cascade  ReferentialAction = "CASCADE"
+	SetNull  ReferentialAction = "SET NULL"
+	Restrict ReferentialAction = "RESTRICT"
+)
+
+// ForeignKey représente une référence d'un attribut vers la colonne d'une autre table
+type ForeignKey struct {
+	table    string
+	column   string
+	onDelete ReferentialAction
+	onUpdate ReferentialAction
+}
+
 // Attribute représente une colonne de table avec ses contraintes
 type Attribute struct {
 	name        string
 	dataType    AttributeType
 	constraints []string
+	reference   *ForeignKey
+	enum        *Enum
+	// defaultExpr est l'expression SQL de la clause DEFAULT, vide sans valeur par défaut
+	defaultExpr string
+	// defaultErr est l'erreur de conversion de la valeur passée à Default
+	defaultErr error
+	// generatedExpr est l'expression d'une colonne GENERATED ALWAYS AS (...) STORED
+	generatedExpr string
+	// identity est le mode d'une colonne GENERATED ... AS IDENTITY, vide pour les autres colonnes
+	identity IdentityGeneration
+	// inPrimaryKey indique une colonne d'une clé primaire déclarée par PrimaryKey
+	inPrimaryKey bool
+	checks       []*Constraint
 }
 
 // AttributeBuilder permet de construire un attribut avec le pattern builder
@@ -42,9 +143,61 @@ func (ab *AttributeBuilder) Unique() *AttributeBuilder {
 	return ab
 }
 
+// Default définit la valeur par défaut de la colonne. La valeur Go est convertie en
+// littéral SQL : chaînes, booléens, nombres, db.Decimal, time.Time ou nil (NULL).
+// Une valeur d'un autre type est signalée par une erreur à la validation du schéma.
+func (ab *AttributeBuilder) Default(value interface{}) *AttributeBuilder {
+	ab.attribute.defaultExpr, ab.attribute.defaultErr = sqlLiteral(value)
+	return ab
+}
+
+// DefaultExpr définit une expression SQL comme valeur par défaut de la colonne (ex: "now()")
+func (ab *AttributeBuilder) DefaultExpr(expr string) *AttributeBuilder {
+	ab.attribute.defaultExpr = expr
+	return ab
+}
+
+// GeneratedAlwaysAs déclare une colonne calculée par PostgreSQL à partir des autres colonnes
+// de la ligne (GENERATED ALWAYS AS (expr) STORED). Elle ne peut pas être écrite.
+func (ab *AttributeBuilder) GeneratedAlwaysAs(expr string) *AttributeBuilder {
+	ab.attribute.generatedExpr = expr
+	return ab
+}
+
+// References déclare une clé étrangère vers la colonne d'une autre table
+func (ab *AttributeBuilder) References(table, column string) *AttributeBuilder {
+	ab.attribute.reference = &ForeignKey{
+		table:  table,
+		column: column,
+	}
+	return ab
+}
+
+// OnDelete définit l'action à effectuer lorsque la ligne référencée est supprimée
+func (ab *AttributeBuilder) OnDelete(action ReferentialAction) *AttributeBuilder {
+	if ab.attribute.reference == nil {
+		panic(fmt.Sprintf("OnDelete doit être appelé après References pour la colonne %s", ab.attribute.name))
+	}
+	ab.attribute.reference.onDelete = action
+	return ab
+}
+
+// OnUpdate définit l'action à effectuer lorsque la ligne référencée est modifiée
+func (ab *AttributeBuilder) OnUpdate(action ReferentialAction) *AttributeBuilder {
+	if ab.attribute.reference == nil {
+		panic(fmt.Sprintf("OnUpdate doit être appelé après References pour la colonne %s", ab.attribute.name))
+	}
+	ab.attribute.reference.onUpdate = action
+	return ab
+}
+
 // Build finalise la construction de l'attribut et l'ajoute à la table
 func (ab *AttributeBuilder) Build() *TableBuilder {
+	if ab.attribute.generatedExpr != "" && ab.attribute.defaultExpr != "" {
+		panic(fmt.Sprintf("la colonne calculée %s ne peut pas avoir de valeur par défaut", ab.attribute.name))
+	}
 	if ab.tableBuilder != nil {
+		ab.attribute.inPrimaryKey = ab.tableBuilder.isKeyColumn(ab.attribute.name)
 		ab.tableBuilder.attributes = append(ab.tableBuilder.attributes, ab.attribute)
 		return ab.tableBuilder
 	}
@@ -53,24 +206,25 @@ func (ab *AttributeBuilder) Build() *TableBuilder {
 
 // TableBuilder permet de construire une table avec le pattern builder
 type TableBuilder struct {
-	name       string
-	attributes []*Attribute
+	name        string
+	attributes  []*Attribute
+	constraints []*Constraint
+	indexes     []*Index
+	// key est la stratégie de la colonne id ajoutée par NewTable, vide si elle a été retirée
+	key KeyStrategy
 }
 
-// NewTable crée un nouveau builder de table avec l'ID auto-incrémenté obligatoire
+// NewTable crée un nouveau builder de table avec une clé primaire id SERIAL auto-incrémentée.
+// WithKey change sa stratégie, WithoutKey et PrimaryKey la retirent.
 func NewTable(name string) *TableBuilder {
 	tb := &TableBuilder{
 		name:       name,
 		attributes: make([]*Attribute, 0),
+		key:        SerialKey,
 	}
 	
-	// Ajout automatique de l'ID auto-incrémenté (obligatoire)
-	idAttribute := &Attribute{
-		name:        "id",
-		dataType:    "SERIAL",
-		constraints: []string{"PRIMARY KEY"},
-	}
-	tb.attributes = append(tb.attributes, idAttribute)
+	// Ajout automatique de l'ID auto-incrémenté
+	tb.attributes = append(tb.attributes, newKeyAttribute(SerialKey))
 	
 	return tb
 }
@@ -94,25 +248,74 @@ func (tb *TableBuilder) BuildSQL() string {
 	var columns []string
 	
 	for _, attr := range tb.attributes {
-		definition := fmt.Sprintf("\"%s\" %s", attr.name, attr.dataType)
-		
-		// Ajout des contraintes
-		for _, constraint := range attr.constraints {
-			definition += " " + constraint
-		}
-		
-		columns = append(columns, definition)
+		columns = append(columns, attr.buildSQL())
+	}
+
+	// Les contraintes de table suivent les colonnes
+	for _, constraint := range tb.constraints {
+		columns = append(columns, constraint.buildSQL())
 	}
 	
 	columnsStr := strings.Join(columns, ", ")
 	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS \"%s\" (%s)", tb.name, columnsStr)
 }
 
+// buildSQL retourne la définition SQL de la colonne avec ses contraintes
+func (a *Attribute) buildSQL() string {
+	definition := fmt.Sprintf("\"%s\" %s", a.name, a.dataType)
+
+	if a.identity != "" {
+		definition += fmt.Sprintf(" GENERATED %s AS IDENTITY", a.identity)
+	}
+	if a.generatedExpr != "" {
+		definition += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", a.generatedExpr)
+	}
+	if a.defaultExpr != "" {
+		definition += " DEFAULT " + a.defaultExpr
+	}
+
+	// Ajout des contraintes
+	for _, constraint := range a.constraints {
+		definition += " " + constraint
+	}
+
+	for _, check := range a.checks {
+		definition += " " + check.buildSQL()
+	}
+
+	// Ajout de la clé étrangère
+	if a.reference != nil {
+		definition += " " + a.reference.buildSQL()
+	}
+
+	return definition
+}
+
+// buildSQL retourne la clause REFERENCES de la clé étrangère
+func (fk *ForeignKey) buildSQL() string {
+	clause := fmt.Sprintf("REFERENCES \"%s\" (\"%s\")", fk.table, fk.column)
+	if fk.onDelete != "" {
+		clause += " ON DELETE " + string(fk.onDelete)
+	}
+	if fk.onUpdate != "" {
+		clause += " ON UPDATE " + string(fk.onUpdate)
+	}
+	return clause
+}
+
 // CreateTable crée une nouvelle table dans la base de données en utilisant un TableBuilder
 func (c *Connection) CreateTable(tableBuilder *TableBuilder) error {
+	return c.CreateTableContext(context.Background(), tableBuilder)
+}
+
+// CreateTableContext crée une table comme CreateTable en respectant le contexte
+func (c *Connection) CreateTableContext(ctx context.Context, tableBuilder *TableBuilder) error {
+	if err := tableBuilder.validateDefaults(); err != nil {
+		return err
+	}
 	query := tableBuilder.BuildSQL()
 	
-	_, err := c.db.Exec(query)
+	_, err := c.db.ExecContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
@@ -131,6 +334,21 @@ func (tb *TableBuilder) GetAttributes() []*Attribute {
 	return tb.attributes
 }
 
+// GetDependencies retourne les noms des tables référencées par les clés étrangères
+// de la table, sans doublon et sans la table elle-même
+func (tb *TableBuilder) GetDependencies() []string {
+	var dependencies []string
+	seen := make(map[string]bool)
+	for _, attr := range tb.attributes {
+		if attr.reference == nil || attr.reference.table == tb.name || seen[attr.reference.table] {
+			continue
+		}
+		seen[attr.reference.table] = true
+		dependencies = append(dependencies, attr.reference.table)
+	}
+	return dependencies
+}
+
 // GetName retourne le nom de l'attribut
 func (a *Attribute) GetName() string {
 	return a.name
@@ -146,6 +364,11 @@ func (a *Attribute) GetConstraints() []string {
 	return a.constraints
 }
 
+// GetReference retourne la clé étrangère de l'attribut, ou nil s'il n'en a pas
+func (a *Attribute) GetReference() *ForeignKey {
+	return a.reference
+}
+
 // IsRequired vérifie si l'attribut a la contrainte NOT NULL
 func (a *Attribute) IsRequired() bool {
 	for _, constraint := range a.constraints {
@@ -166,20 +389,162 @@ func (a *Attribute) IsUnique() bool {
 	return false
 }
 
-// GetGoType retourne le type Go correspondant au type de données
+// IsPrimaryKey vérifie si l'attribut a la contrainte PRIMARY KEY ou fait partie
+// de la clé primaire composite de la table
+func (a *Attribute) IsPrimaryKey() bool {
+	if a.inPrimaryKey {
+		return true
+	}
+	for _, constraint := range a.constraints {
+		if constraint == "PRIMARY KEY" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDefault vérifie si la colonne a une valeur par défaut
+func (a *Attribute) HasDefault() bool {
+	return a.defaultExpr != ""
+}
+
+// GetDefault retourne l'expression SQL de la valeur par défaut, vide sans valeur par défaut
+func (a *Attribute) GetDefault() string {
+	return a.defaultExpr
+}
+
+// IsGenerated vérifie si la colonne est calculée par PostgreSQL (GENERATED ALWAYS AS ... STORED)
+func (a *Attribute) IsGenerated() bool {
+	return a.generatedExpr != ""
+}
+
+// GetGeneratedExpr retourne l'expression d'une colonne calculée, vide sinon
+func (a *Attribute) GetGeneratedExpr() string {
+	return a.generatedExpr
+}
+
+// IsRequiredOnInsert vérifie si une insertion doit fournir une valeur pour la colonne :
+// NOT NULL sans valeur par défaut, hors colonnes calculées et auto-incrémentées
+func (a *Attribute) IsRequiredOnInsert() bool {
+	return a.IsRequired() && !a.HasDefault() && !a.IsGenerated() && !a.IsAutoIncrement()
+}
+
+// IsNullable vérifie si la colonne accepte NULL (ni NOT NULL, ni PRIMARY KEY)
+func (a *Attribute) IsNullable() bool {
+	return !a.IsRequired() && !a.IsPrimaryKey()
+}
+
+// GetGoType retourne le type Go correspondant au type de données.
+// Les colonnes nullables sont représentées par un pointeur, nil correspondant à NULL,
+// sauf les tableaux pour lesquels NULL est lu comme un slice nil.
 func (a *Attribute) GetGoType() string {
-	switch a.dataType {
-	case String:
-		return "string"
-	case Integer:
-		return "int"
-	case Float:
-		return "float64"
-	case Boolean:
-		return "bool"
-	case "SERIAL":
-		return "int"
-	default:
+	goType := a.GetBaseGoType()
+	if a.IsNullable() && goType != "interface{}" && !a.IsArray() {
+		return "*" + goType
+	}
+	return goType
+}
+
+// GetBaseGoType retourne le type Go des valeurs non NULL de la colonne.
+// Les types paramétrés (VARCHAR(n), NUMERIC(p,s)...) ont le type Go de leur type de base,
+// les types énumérés le type Go généré pour eux (ex: PostStatus) et les tableaux de texte, d'entiers, de flottants et de booléens sont des slices.
+func (a *Attribute) GetBaseGoType() string {
+	if a.enum != nil {
+		return a.enum.GetGoType()
+	}
+
+	baseType := canonicalType(string(a.dataType))
+	if a.IsArray() {
+		element := &Attribute{dataType: AttributeType(strings.TrimSuffix(baseType, "[]"))}
+		if goType, known := arrayGoTypes[element.GetBaseGoType()]; known {
+			return goType
+		}
 		return "interface{}"
 	}
+
+	if open := strings.Index(baseType, "("); open >= 0 {
+		baseType = baseType[:open]
+	}
+	if goType, known := goTypes[baseType]; known {
+		return goType
+	}
+	return "interface{}"
+}
+
+// IsArray vérifie si la colonne est un tableau PostgreSQL (ex: TEXT[])
+func (a *Attribute) IsArray() bool {
+	return strings.HasSuffix(strings.TrimSpace(string(a.dataType)), "[]")
+}
+
+// sqlLiteral convertit une valeur Go en littéral SQL pour une clause DEFAULT
+func sqlLiteral(value interface{}) (string, error) {
+	if value == nil {
+		return "NULL", nil
+	}
+	if t, ok := value.(time.Time); ok {
+		return quoteLiteral(t.Format(time.RFC3339Nano)), nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return quoteLiteral(v.String()), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		// NaN et les infinis n'ont pas de littéral numérique, seulement une forme textuelle
+		switch f := v.Float(); {
+		case math.IsNaN(f):
+			return "'NaN'::double precision", nil
+		case math.IsInf(f, 1):
+			return "'Infinity'::double precision", nil
+		case math.IsInf(f, -1):
+			return "'-Infinity'::double precision", nil
+		}
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	default:
+		return "", fmt.Errorf("type de valeur par défaut non supporté: %T", value)
+	}
+}
+
+// validateDefaults vérifie que les valeurs passées à Default ont été converties en littéraux SQL
+func (tb *TableBuilder) validateDefaults() error {
+	for _, attr := range tb.attributes {
+		if attr.defaultErr != nil {
+			return fmt.Errorf("valeur par défaut invalide pour la colonne '%s.%s': %w", tb.name, attr.name, attr.defaultErr)
+		}
+	}
+	return nil
+}
+
+// quoteLiteral entoure une chaîne d'apostrophes en doublant celles qu'elle contient
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// GetTable retourne le nom de la table référencée
+func (fk *ForeignKey) GetTable() string {
+	return fk.table
+}
+
+// GetColumn retourne le nom de la colonne référencée
+func (fk *ForeignKey) GetColumn() string {
+	return fk.column
+}
+
+// GetOnDelete retourne l'action ON DELETE, vide si aucune n'a été définie
+func (fk *ForeignKey) GetOnDelete() ReferentialAction {
+	return fk.onDelete
+}
+
+// GetOnUpdate retourne l'action ON UPDATE, vide si aucune n'a été définie
+func (fk *ForeignKey) GetOnUpdate() ReferentialAction {
+	return fk.onUpdate
 }
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr string
+	}{
+		{"nil", nil, "NULL", ""},
+		{"chaîne", "l'été", "'l''été'", ""},
+		{"booléen", true, "TRUE", ""},
+		{"entier", int64(-42), "-42", ""},
+		{"entier non signé", uint8(7), "7", ""},
+		{"flottant", 0.5, "0.5", ""},
+		{"NaN", math.NaN(), "'NaN'::double precision", ""},
+		{"infini", math.Inf(1), "'Infinity'::double precision", ""},
+		{"moins l'infini", float32(math.Inf(-1)), "'-Infinity'::double precision", ""},
+		{"Decimal", Decimal("1234.50"), "'1234.50'", ""},
+		{"date", time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), "'2024-01-15T09:30:00Z'", ""},
+		{"type non supporté", []string{"a"}, "", "type de valeur par défaut non supporté: []string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sqlLiteral(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("sqlLiteral(%v) = %s, attendu %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	posts := NewTable("posts").
+		AddAttribute("views", Integer).NotNull().Default(0).Build().
+		AddAttribute("ratio", Float).Default(math.NaN()).Build()
+	want := `CREATE TABLE IF NOT EXISTS "posts" ("id" SERIAL PRIMARY KEY, "views" INTEGER DEFAULT 0 NOT NULL, "ratio" FLOAT DEFAULT 'NaN'::double precision)`
+	if got := posts.BuildSQL(); got != want {
+		t.Errorf("BuildSQL = %s, attendu %s", got, want)
+	}
+
+	// Une valeur non supportée est signalée par la validation du schéma, sans panique
+	schema := NewSchema().MustRegister(NewTable("tags").AddAttribute("labels", ArrayOf(Text)).Default([]string{"a"}).Build())
+	err := schema.Validate()
+	if err == nil || !strings.Contains(err.Error(), "valeur par défaut invalide pour la colonne 'tags.labels'") {
+		t.Fatalf("erreur = %v, attendu une valeur par défaut invalide", err)
+	}
+}
+
+func TestGetGoType(t *testing.T) {
+	table := NewTable("items").WithoutKey().
+		AddAttribute("name", Varchar(100)).NotNull().Build().
+		AddAttribute("nickname", Text).Build().
+		AddAttribute("quantity", Integer).NotNull().Build().
+		AddAttribute("stock", BigInt).Build().
+		AddAttribute("price", NumericOf(15, 2)).NotNull().Build().
+		AddAttribute("discount", Numeric).Build().
+		AddAttribute("external_id", UUID).NotNull().Build().
+		AddAttribute("created_at", TimestampTZ).NotNull().Build().
+		AddAttribute("metadata", JSONB).Build().
+		AddAttribute("tags", ArrayOf(Text)).Build().
+		AddAttribute("scores", ArrayOf(Integer)).NotNull().Build().
+		AddAttribute("ratios", ArrayOf(NumericOf(5, 2))).Build().
+		AddAttribute("location", "POINT").Build()
+
+	want := map[string]string{
+		"name":        "string",
+		"nickname":    "*string",
+		"quantity":    "int",
+		"stock":       "*int64",
+		"price":       "db.Decimal",
+		"discount":    "*db.Decimal",
+		"external_id": "string",
+		"created_at":  "time.Time",
+		"metadata":    "*json.RawMessage",
+		"tags":        "[]string",
+		"scores":      "[]int64",
+		"ratios":      "interface{}",
+		"location":    "interface{}",
+	}
+	for _, attr := range table.GetAttributes() {
+		t.Run(attr.GetName(), func(t *testing.T) {
+			if got := attr.GetGoType(); got != want[attr.GetName()] {
+				t.Errorf("GetGoType = %s, attendu %s", got, want[attr.GetName()])
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Transaction exécute fn dans une transaction. La transaction est validée si fn
+// retourne nil et annulée si fn retourne une erreur ou panique (la panique est
+// ensuite propagée). Le *sql.Tx reçu implémente query.Executor et peut être passé
+// à la place de la connexion aux méthodes Execute du code généré.
+func (c *Connection) Transaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return transaction(ctx, c.db, fn)
+}
+
+// txBeginner représente ce qui ouvre une transaction : le pool *sql.DB ou une
+// connexion dédiée *sql.Conn
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// transaction exécute fn dans une transaction ouverte par beginner, comme Transaction
+func transaction(ctx context.Context, beginner txBeginner, fn func(tx *sql.Tx) error) error {
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
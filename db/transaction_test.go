@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	errFailed := errors.New("échec")
+
+	tests := []struct {
+		name      string
+		fn        func(tx *sql.Tx) error
+		wantErr   error
+		wantPanic bool
+		want      []string
+	}{
+		{
+			name: "validée",
+			fn: func(tx *sql.Tx) error {
+				_, err := tx.Exec("UPDATE accounts SET balance = 0")
+				return err
+			},
+			want: []string{"1: BEGIN", "1: UPDATE accounts SET balance = 0", "1: COMMIT"},
+		},
+		{
+			name: "annulée sur erreur",
+			fn: func(tx *sql.Tx) error {
+				tx.Exec("UPDATE accounts SET balance = 0")
+				return errFailed
+			},
+			wantErr: errFailed,
+			want:    []string{"1: BEGIN", "1: UPDATE accounts SET balance = 0", "1: ROLLBACK"},
+		},
+		{
+			name: "annulée sur panique",
+			fn: func(tx *sql.Tx) error {
+				tx.Exec("UPDATE accounts SET balance = 0")
+				panic("panique prévue")
+			},
+			wantPanic: true,
+			want:      []string{"1: BEGIN", "1: UPDATE accounts SET balance = 0", "1: ROLLBACK"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, recorder := newRecordingConnection("")
+			defer conn.Close()
+
+			func() {
+				defer func() {
+					// La panique est propagée après l'annulation
+					if r := recover(); (r != nil) != tt.wantPanic {
+						t.Errorf("panique = %v, attendu %v", r, tt.wantPanic)
+					}
+				}()
+				if err := conn.Transaction(context.Background(), tt.fn); !errors.Is(err, tt.wantErr) {
+					t.Errorf("erreur = %v, attendu %v", err, tt.wantErr)
+				}
+			}()
+
+			if got := recorder.statements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("requêtes = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
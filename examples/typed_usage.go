@@ -1,11 +1,16 @@
 package examples
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"postgo/db"
+	"postgo/db/query"
 	"postgo/generated"
 	"postgo/logging"
+	"time"
 
 	_ "github.com/lib/pq"
 )
@@ -52,6 +57,7 @@ func DemoTypedInserts() {
 		SetEmployeeCount(150).
 		SetRevenue(1250000.50).
 		SetIsPublic(true).
+		SetCapital(db.Decimal("250000.00")). // NUMERIC(15,2) sans perte de précision
 		Execute(conn)
 	
 	if err != nil {
@@ -66,6 +72,10 @@ func DemoTypedInserts() {
 		SetTitle("Mon premier article").
 		SetContent("Ceci est le contenu de mon premier article de blog.").
 		SetPublished(true).
+		SetPublishedAt(time.Now()).
+		SetMetadata(json.RawMessage(`{"source": "blog"}`)).
+		SetTags([]string{"go", "postgres"}).
+		SetStatus(generated.PostStatusPublished).
 		Execute(conn)
 	
 	if err != nil {
@@ -108,7 +118,7 @@ func DemoTypedInserts() {
 	err = generated.Users.Update().
 		SetName("John Doe Updated").
 		SetEmail("john.updated@example.com").
-		Where("name = 'John Doe'").
+		Where(generated.Users.Name.Eq("John Doe")).
 		Execute(conn)
 	
 	if err != nil {
@@ -122,7 +132,7 @@ func DemoTypedInserts() {
 	err = generated.Companies.Update().
 		SetEmployeeCount(200).
 		SetRevenue(2500000.75).
-		Where("name = 'Tech Corp'").
+		Where(generated.Companies.Name.Eq("Tech Corp")).
 		Execute(conn)
 	
 	if err != nil {
@@ -135,7 +145,7 @@ func DemoTypedInserts() {
 	fmt.Println("\n--- Update d'une seule colonne ---")
 	err = generated.Posts.Update().
 		SetPublished(false).
-		Where("title = 'Mon premier article'").
+		Where(generated.Posts.Title.Eq("Mon premier article")).
 		Execute(conn)
 	
 	if err != nil {
@@ -144,10 +154,23 @@ func DemoTypedInserts() {
 		fmt.Println("✓ Post mis à jour avec succès!")
 	}
 
+	// 8 bis. Effacer une colonne nullable (NULL explicite)
+	fmt.Println("\n--- Update vers NULL ---")
+	err = generated.Companies.Update().
+		SetDescriptionNull().
+		Where(generated.Companies.Name.Eq("Tech Corp")).
+		Execute(conn)
+	
+	if err != nil {
+		fmt.Printf("Erreur lors de l'update: %v\n", err)
+	} else {
+		fmt.Println("✓ Description de l'entreprise effacée!")
+	}
+
 	// 9. Test de validation "aucune colonne à mettre à jour"
 	fmt.Println("\n--- Test de validation des updates vides ---")
 	err = generated.Users.Update().
-		Where("id = 1").
+		Where(generated.Users.Id.Eq(1)).
 		Execute(conn)
 	
 	if err != nil {
@@ -156,6 +179,19 @@ func DemoTypedInserts() {
 		fmt.Println("❌ La validation a échoué - aucune erreur détectée")
 	}
 
+	// 9 bis. Validation des types énumérés avant l'envoi à PostgreSQL
+	fmt.Println("\n--- Test de validation des types énumérés ---")
+	err = generated.Posts.Update().
+		SetStatus(generated.PostStatus("supprimé")).
+		Where(generated.Posts.Title.Eq("Mon premier article")).
+		Execute(conn)
+
+	if err != nil {
+		fmt.Printf("✓ Validation réussie - Erreur attendue: %v\n", err)
+	} else {
+		fmt.Println("❌ La validation a échoué - aucune erreur détectée")
+	}
+
 	// 10. Test de prévention de duplication de colonnes
 	fmt.Println("\n--- Test de prévention de duplication ---")
 	testDuplicationPrevention()
@@ -190,7 +226,7 @@ func DemoTypedInserts() {
 	// 11. Suppression d'un utilisateur spécifique
 	fmt.Println("\n--- Suppression d'un utilisateur ---")
 	err = generated.Users.Delete().
-		Where("email = 'delete.me@example.com'").
+		Where(generated.Users.Email.Eq("delete.me@example.com")).
 		Execute(conn)
 	
 	if err != nil {
@@ -202,7 +238,7 @@ func DemoTypedInserts() {
 	// 12. Suppression d'une entreprise par nom (plus sûr que par ID)
 	fmt.Println("\n--- Suppression d'une entreprise ---")
 	err = generated.Companies.Delete().
-		Where("name = 'Tech Corp'").
+		Where(generated.Companies.Name.Eq("Tech Corp")).
 		Execute(conn)
 	
 	if err != nil {
@@ -214,8 +250,8 @@ func DemoTypedInserts() {
 	// 13. Suppression avec conditions multiples
 	fmt.Println("\n--- Suppression avec conditions multiples ---")
 	err = generated.Posts.Delete().
-		Where("published = false").
-		Where("title LIKE '%supprimer%'").
+		Where(generated.Posts.Published.IsFalse()).
+		Where(generated.Posts.Title.Like("%supprimer%")).
 		Execute(conn)
 	
 	if err != nil {
@@ -227,7 +263,7 @@ func DemoTypedInserts() {
 	// 14. Suppression de catégories par slug
 	fmt.Println("\n--- Suppression de catégories ---")
 	err = generated.Categories.Delete().
-		Where("slug = 'technology'").
+		Where(generated.Categories.Slug.Eq("technology")).
 		Execute(conn)
 	
 	if err != nil {
@@ -239,7 +275,7 @@ func DemoTypedInserts() {
 	// 15. Test de construction de requête sans exécution
 	fmt.Println("\n--- Test de construction de requête DELETE ---")
 	sqlQuery, args := generated.Users.Delete().
-		Where("email LIKE '%@example.com'").
+		Where(generated.Users.Email.Like("%@example.com")).
 		Build()
 	
 	fmt.Printf("Requête SQL générée: %s\n", sqlQuery)
@@ -299,7 +335,7 @@ func DemoTypedInserts() {
 	fmt.Println("\n3. Test Select avec WHERE:")
 	users, err = generated.Users.Select().
 	SelectAll().
-	Where("name LIKE '%John%'").
+	Where(generated.Users.Name.Like("%John%")).
 	Execute(conn)
 	if err != nil {
 		log.Printf("Erreur lors du Select avec WHERE: %v", err)
@@ -310,6 +346,20 @@ func DemoTypedInserts() {
 		}
 	}
 
+	// 19 bis. Select sur une colonne tableau
+	fmt.Println("\n3 bis. Test Select sur les tags:")
+	posts, err := generated.Posts.Select().
+		SelectAll().
+		Where(generated.Posts.Tags.Overlaps("go", "rust")).
+		Execute(conn)
+	if err != nil {
+		log.Printf("Erreur lors du Select sur les tags: %v", err)
+	} else {
+		for _, post := range posts {
+			fmt.Printf("  - %s %v\n", post.Title, post.Tags)
+		}
+	}
+
 	// 20. Select avec WHERE typé
 	fmt.Println("\n4. Test Select avec WHERE typé:")
 	users, err = generated.Users.Select().
@@ -337,6 +387,199 @@ func DemoTypedInserts() {
 		fmt.Printf("Utilisateur avec ID n°1 => ID: %d, Name: %s, Email: %s\n", user.Id, user.Name, user.Email)
 	}
 
+	// === EXEMPLE DE TRANSACTION ===
+
+	// 22. Insertion atomique d'un utilisateur et de son entreprise
+	fmt.Println("\n--- Transaction: insertion d'un utilisateur et de son entreprise ---")
+	err = conn.Transaction(context.Background(), func(tx *sql.Tx) error {
+		err := generated.Users.Insert().
+			SetName("Bob Founder").
+			SetEmail("bob@startup.example.com").
+			SetPassword("securepassword123").
+			Execute(tx)
+		if err != nil {
+			return err
+		}
+
+		return generated.Companies.Insert().
+			SetName("Bob's Startup").
+			SetIsPublic(false).
+			Execute(tx)
+	})
+
+	if err != nil {
+		fmt.Printf("Transaction annulée: %v\n", err)
+	} else {
+		fmt.Println("✓ Utilisateur et entreprise insérés dans la même transaction!")
+	}
+
+	// === EXEMPLE RETURNING ===
+
+	// 23. Récupérer l'id généré pour l'utiliser comme clé étrangère
+	fmt.Println("\n--- Insertion avec RETURNING ---")
+	author, err := generated.Users.Insert().
+		SetName("Carol Writer").
+		SetEmail("carol@example.com").
+		SetPassword("securepassword123").
+		ExecuteReturning(conn)
+
+	if err != nil {
+		fmt.Printf("Erreur lors de l'insertion: %v\n", err)
+	} else {
+		fmt.Printf("✓ Utilisateur inséré avec l'id %d\n", author.Id)
+
+		post, err := generated.Posts.Insert().
+			SetTitle("Article de Carol").
+			SetContent("Contenu rattaché à son autrice.").
+			SetPublished(true).
+			SetAuthorId(author.Id).
+			ExecuteReturning(conn)
+		if err != nil {
+			fmt.Printf("Erreur lors de l'insertion du post: %v\n", err)
+		} else {
+			// author_id est nullable : le champ est un *int, nil correspondant à NULL
+			fmt.Printf("✓ Post %d inséré pour l'auteur %d\n", post.Id, *post.AuthorId)
+		}
+	}
+
+	// === EXEMPLE UPSERT ===
+
+	// 24. Écriture idempotente : insérer ou mettre à jour selon l'email
+	fmt.Println("\n--- Upsert d'un utilisateur ---")
+	for _, name := range []string{"Dave Sync", "Dave Sync (mis à jour)"} {
+		user, err := generated.Users.Upsert().
+			OnConflictEmail().
+			SetName(name).
+			SetEmail("dave@example.com").
+			SetPassword("securepassword123").
+			ExecuteReturning(conn)
+		if err != nil {
+			fmt.Printf("Erreur lors de l'upsert: %v\n", err)
+			break
+		}
+		fmt.Printf("✓ Utilisateur %d enregistré: %s\n", user.Id, user.Name)
+	}
+
+	// === EXEMPLE CLÉ PRIMAIRE COMPOSITE ===
+
+	// 25. Table de liaison sans colonne id : la clé primaire est (post_id, category_id)
+	fmt.Println("\n--- Liaison d'un post à une catégorie ---")
+	category, err := generated.Categories.Upsert().
+		OnConflictSlug().
+		SetSlug("databases").
+		SetDisplayName("Bases de données").
+		ExecuteReturning(conn)
+	if err != nil {
+		fmt.Printf("Erreur lors de l'upsert de la catégorie: %v\n", err)
+	} else if post, err := generated.Posts.Insert().SetTitle("Index et clés primaires").ExecuteReturning(conn); err != nil {
+		fmt.Printf("Erreur lors de l'insertion du post: %v\n", err)
+	} else {
+		// La seconde liaison est ignorée grâce à la clé primaire composite
+		for range 2 {
+			err = generated.PostCategories.Upsert().
+				OnConflictPostIdCategoryId().
+				DoNothing().
+				SetPostId(post.Id).
+				SetCategoryId(category.Id).
+				Execute(conn)
+			if err != nil {
+				fmt.Printf("Erreur lors de la liaison: %v\n", err)
+				break
+			}
+		}
+		fmt.Printf("✓ Post %d lié à la catégorie '%s'\n", post.Id, category.Slug)
+	}
+
+	// === EXEMPLE JOINTURE ===
+
+	// 26. Posts publiés avec leur auteur, lus dans generated.PostWithAuthor
+	fmt.Println("\n--- Posts et leurs auteurs ---")
+	postsWithAuthor, err := generated.Posts.Select().JoinAuthor().
+		Where(generated.Posts.Published.IsTrue()).
+		Execute(conn)
+	if err != nil {
+		fmt.Printf("Erreur lors de la jointure: %v\n", err)
+	} else {
+		for _, post := range postsWithAuthor {
+			fmt.Printf("✓ '%s' par %s\n", post.Title, post.Author.Name)
+		}
+	}
+
+	// === EXEMPLE AGRÉGATS ===
+
+	// 27. Nombre de posts par auteur, lu dans un struct de l'appelant
+	fmt.Println("\n--- Posts par auteur ---")
+	type authorStats struct {
+		AuthorId *int  `db:"author_id"`
+		Total    int64 `db:"total"`
+	}
+	stats, err := query.Scan[authorStats](context.Background(), conn, generated.Posts.Select().
+		Aggregate(generated.Posts.AuthorId, query.Count().As("total")).
+		GroupBy(generated.Posts.AuthorId).
+		Having(query.Count().Gte(1)))
+	if err != nil {
+		fmt.Printf("Erreur lors de l'agrégation: %v\n", err)
+	} else {
+		for _, stat := range stats {
+			if stat.AuthorId != nil {
+				fmt.Printf("✓ Auteur %d: %d post(s)\n", *stat.AuthorId, stat.Total)
+			}
+		}
+	}
+
+	// === EXEMPLE TRI ET LIMITE ===
+
+	// 28. Les trois utilisateurs les plus récents
+	fmt.Println("\n--- Utilisateurs les plus récents ---")
+	latestUsers, err := generated.Users.Select().SelectAll().
+		OrderByCreatedAtDesc().
+		Limit(3).
+		Execute(conn)
+	if err != nil {
+		fmt.Printf("Erreur lors de la sélection: %v\n", err)
+	} else {
+		for _, user := range latestUsers {
+			fmt.Printf("✓ %s (%s)\n", user.Name, user.Email)
+		}
+	}
+
+	// === EXEMPLE PAGINATION ===
+
+	// 29. Parcours des utilisateurs par pages de 2, sans OFFSET
+	fmt.Println("\n--- Pagination par curseur ---")
+	cursor := ""
+	for pageNumber := 1; ; pageNumber++ {
+		page, err := generated.Users.Select().SelectAll().
+			OrderByCreatedAtDesc().
+			After(cursor).
+			Page(conn, 2)
+		if err != nil {
+			fmt.Printf("Erreur lors de la pagination: %v\n", err)
+			break
+		}
+		fmt.Printf("✓ Page %d: %d utilisateur(s)\n", pageNumber, len(page.Items))
+		if !page.HasNext() {
+			break
+		}
+		cursor = page.NextCursor()
+	}
+
+	// === EXEMPLE ITÉRATEUR ===
+
+	// 30. Parcours ligne à ligne, arrêté après trois utilisateurs
+	fmt.Println("\n--- Parcours des utilisateurs ---")
+	count := 0
+	for user, err := range generated.Users.Select().SelectAll().OrderByIdAsc().Iter(context.Background(), conn) {
+		if err != nil {
+			fmt.Printf("Erreur lors du parcours: %v\n", err)
+			break
+		}
+		fmt.Printf("✓ %d: %s\n", user.Id, user.Name)
+		if count++; count == 3 {
+			break
+		}
+	}
+
 	fmt.Println("\n=== Démonstration terminée ===")
 }
 
@@ -351,7 +594,7 @@ func testDuplicationPrevention() {
 	generated.Categories.Update().
 		SetSlug("tech").
 		SetSlug("technology"). // Tentative de redéfinir la même colonne
-		Where("id = 1")
+		Where(generated.Categories.Id.Eq(1))
 	
 	fmt.Println("❌ La validation a échoué - aucun panic détecté")
 }
@@ -3,3 +3,5 @@ module postgo
 go 1.23.6
 
 require github.com/lib/pq v1.10.9
+
+require gopkg.in/yaml.v3 v3.0.1